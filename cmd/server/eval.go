@@ -0,0 +1,150 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/adapter/document"
+	"github.com/mfreyr/deckgen/internal/adapter/llm"
+	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/eval"
+	"github.com/mfreyr/deckgen/internal/prompt"
+	storage "github.com/mfreyr/deckgen/internal/repository"
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/mfreyr/deckgen/internal/taxonomy"
+)
+
+// embeddedPrompts names the prompts built into the binary in the -prompts flag.
+const embeddedPrompts = "embedded"
+
+// runEval implements "deckgen eval": it parses a labelled dataset with every combination of the
+// given providers and prompt directories, and writes a report comparing their scores.
+func runEval(args []string) error {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	configPath := flags.String("config", "config.yaml", "path to the config file")
+	dataset := flags.String("dataset", "", "directory holding the resumes and job-ads subdirectories of labelled documents")
+	providersFlag := flags.String("providers", "", "comma-separated providers to evaluate, all enabled providers by default")
+	promptsFlag := flags.String("prompts", "", "comma-separated prompt directories to evaluate, \"embedded\" for the built-in prompts, the configured prompts by default")
+	locale := flags.String("locale", "", "locale of the prompts")
+	out := flags.String("out", "", "file to write the report to, standard output by default")
+	format := flags.String("format", "markdown", "report format: markdown or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dataset == "" {
+		return errors.New("-dataset is required")
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown format '%s', expected markdown or json", *format)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	cases, err := eval.LoadDataset(*dataset)
+	if err != nil {
+		return err
+	}
+	providers := splitList(*providersFlag)
+	if len(providers) == 0 {
+		for name, provider := range cfg.LLMProviders {
+			if provider.Enabled {
+				providers = append(providers, name)
+			}
+		}
+		sort.Strings(providers)
+	}
+	if len(providers) == 0 {
+		return errors.New("no provider is enabled")
+	}
+	promptDirs := splitList(*promptsFlag)
+	if len(promptDirs) == 0 {
+		promptDirs = []string{cmp.Or(cfg.Prompts.Dir, embeddedPrompts)}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx = service.WithLocale(ctx, *locale)
+
+	var runs []eval.Run
+	for _, promptDir := range promptDirs {
+		synthesizer, err := newEvalSynthesizer(cfg, promptDir)
+		if err != nil {
+			return fmt.Errorf("prompts %s: %w", promptDir, err)
+		}
+		for _, provider := range providers {
+			cfg.Logger.Info().Str("provider", provider).Str("prompts", promptDir).Int("documents", len(cases)).Msg("evaluating")
+			runs = append(runs, eval.Evaluate(ctx, synthesizer, provider, promptDir, cases))
+		}
+	}
+
+	report := eval.NewReport(*dataset, cases, runs)
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(filepath.Clean(*out))
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteMarkdown(w)
+}
+
+// newEvalSynthesizer builds a service parsing documents like the server does, with the prompts
+// of promptDir and without cache nor budgets.
+func newEvalSynthesizer(cfg config.Config, promptDir string) (*service.SynthesizerService, error) {
+	overrideDir := promptDir
+	if promptDir == embeddedPrompts {
+		overrideDir = ""
+	}
+	prompts, err := prompt.Load(overrideDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := prompts.Require(operationNames()...); err != nil {
+		return nil, err
+	}
+	llmFactory, err := llm.NewLLMFactory(cfg.LLMProviders, prompts, cfg.Logger)
+	if err != nil {
+		return nil, err
+	}
+	skills, err := taxonomy.Load(cfg.Taxonomy.File)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewSynthesizerService(
+		llmFactory,
+		storage.NewMemoryResumeRepo(),
+		newRouting(cfg.LLMRouting),
+		service.WithLogger(cfg.Logger),
+		service.WithTextExtractor(document.NewDocconvExtractor(cfg.Extraction.OCR)),
+		service.WithChunking(cfg.Extraction.Chunking.MaxChars, cfg.Extraction.Chunking.Concurrency),
+		service.WithPseudonymization(pseudonymizedProviders(cfg.LLMProviders)...),
+		service.WithTaxonomy(skills),
+		service.WithCurrencies(newCurrencyTable(cfg.Currency)),
+	), nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,16 +1,34 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 
+	"github.com/mfreyr/deckgen/internal/adapter/cache"
+	"github.com/mfreyr/deckgen/internal/adapter/document"
+	"github.com/mfreyr/deckgen/internal/adapter/llm"
 	"github.com/mfreyr/deckgen/internal/config"
 	"github.com/mfreyr/deckgen/internal/handler"
+	"github.com/mfreyr/deckgen/internal/middleware"
+	"github.com/mfreyr/deckgen/internal/prompt"
+	storage "github.com/mfreyr/deckgen/internal/repository"
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/mfreyr/deckgen/internal/taxonomy"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		if err := runEval(os.Args[2:]); err != nil {
+			log.Fatalf("eval error: %s\n", err)
+		}
+		return
+	}
+
 	configPath := flag.String("config", "config.yaml", "path to the config file")
 	dumpConfig := flag.Bool("dump-config", false, "dump the default config")
 	flag.Parse()
@@ -27,7 +45,56 @@ func main() {
 		log.Fatalf("config error load: %s\n", err)
 	}
 
-	handler := handler.New()
+	prompts, err := prompt.Load(cfg.Prompts.Dir)
+	if err != nil {
+		log.Fatalf("prompts error: %s\n", err)
+	}
+	if err := prompts.Require(operationNames()...); err != nil {
+		log.Fatalf("prompts error: %s\n", err)
+	}
+
+	llmFactory, err := llm.NewLLMFactory(cfg.LLMProviders, prompts, cfg.Logger)
+	if err != nil {
+		log.Fatalf("llm providers error: %s\n", err)
+	}
+
+	skills, err := taxonomy.Load(cfg.Taxonomy.File)
+	if err != nil {
+		log.Fatalf("taxonomy error: %s\n", err)
+	}
+
+	if len(cfg.Currency.Rates) == 0 {
+		cfg.Logger.Warn().Str("base", cfg.Currency.Base).
+			Msg("no exchange rates are configured in currency.rates, rates in other currencies are not converted")
+	}
+
+	opts := []service.Option{
+		service.WithLogger(cfg.Logger),
+		service.WithUsageTracking(storage.NewMemoryUsageRepo(), newPriceTable(cfg.LLMPricing)),
+		service.WithBudgets(newBudgets(cfg.LLMBudgets)),
+		service.WithTextExtractor(document.NewDocconvExtractor(cfg.Extraction.OCR)),
+		service.WithChunking(cfg.Extraction.Chunking.MaxChars, cfg.Extraction.Chunking.Concurrency),
+		service.WithGrounding(service.GroundingMode(cfg.Adaptation.Grounding)),
+		service.WithPseudonymization(pseudonymizedProviders(cfg.LLMProviders)...),
+		service.WithTaxonomy(skills),
+		service.WithCurrencies(newCurrencyTable(cfg.Currency)),
+	}
+	extractionCache, err := newExtractionCache(cfg.Cache)
+	if err != nil {
+		log.Fatalf("extraction cache error: %s\n", err)
+	}
+	if extractionCache != nil {
+		opts = append(opts, service.WithExtractionCache(extractionCache, cfg.Cache.TTL))
+	}
+
+	synthesizer := service.NewSynthesizerService(
+		llmFactory,
+		storage.NewMemoryResumeRepo(),
+		newRouting(cfg.LLMRouting),
+		opts...,
+	)
+
+	handler := middleware.Actor(newAPIKeys(cfg.Auth), middleware.Locale(handler.New(cfg.Logger, synthesizer, llmFactory, prompts)))
 
 	server := &http.Server{
 		Addr:           fmt.Sprintf("127.0.0.1:%d", cfg.Server.Port),
@@ -38,5 +105,102 @@ func main() {
 		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 	}
 
+	sweepCtx, stopSweepers := context.WithCancel(context.Background())
+	llmFactory.StartFileSweepers(sweepCtx)
 	run(cfg.Logger, server)
+	stopSweepers()
+}
+
+func newRouting(cfg map[string]config.LLMRouteConfig) service.Routing {
+	routing := make(service.Routing, len(cfg))
+	for operation, route := range cfg {
+		providers := make([]service.LLMProviderName, len(route.Providers))
+		for i, name := range route.Providers {
+			providers[i] = service.LLMProviderName(name)
+		}
+		routing[service.Operation(operation)] = service.ProviderRoute{
+			Providers: providers,
+			Timeout:   route.Timeout,
+		}
+	}
+	return routing
+}
+
+func newPriceTable(cfg map[string]config.ModelPriceConfig) service.PriceTable {
+	prices := make(service.PriceTable, len(cfg))
+	for modelName, price := range cfg {
+		prices[modelName] = service.ModelPrice{
+			InputPerMillion:       price.InputPerMillion,
+			CachedInputPerMillion: price.CachedInputPerMillion,
+			OutputPerMillion:      price.OutputPerMillion,
+		}
+	}
+	return prices
+}
+
+func newCurrencyTable(cfg config.CurrencyConfig) service.CurrencyTable {
+	rates := make(map[string]float64, len(cfg.Rates))
+	for code, rate := range cfg.Rates {
+		rates[strings.ToUpper(code)] = rate
+	}
+	return service.CurrencyTable{
+		Base:         strings.ToUpper(cfg.Base),
+		Rates:        rates,
+		HoursPerDay:  cfg.HoursPerDay,
+		DaysPerMonth: cfg.DaysPerMonth,
+	}
+}
+
+func newAPIKeys(cfg config.AuthConfig) []middleware.APIKey {
+	keys := make([]middleware.APIKey, len(cfg.APIKeys))
+	for i, key := range cfg.APIKeys {
+		keys[i] = middleware.APIKey{
+			Key:   key.Key,
+			Actor: service.Actor{TenantID: key.TenantID, UserID: key.UserID, Admin: key.Admin},
+		}
+	}
+	return keys
+}
+
+func newBudgets(cfg []config.BudgetConfig) []service.Budget {
+	budgets := make([]service.Budget, len(cfg))
+	for i, budget := range cfg {
+		budgets[i] = service.Budget{
+			Name:         budget.Name,
+			Scope:        service.BudgetScope(budget.Scope),
+			Key:          budget.Key,
+			SoftLimitUSD: budget.SoftLimitUSD,
+			HardLimitUSD: budget.HardLimitUSD,
+		}
+	}
+	return budgets
+}
+
+func pseudonymizedProviders(cfg map[string]config.LLMProviderConfig) []service.LLMProviderName {
+	var names []service.LLMProviderName
+	for name, provider := range cfg {
+		if !provider.DisablePseudonymization {
+			names = append(names, service.LLMProviderName(name))
+		}
+	}
+	return names
+}
+
+func newExtractionCache(cfg config.CacheConfig) (service.ExtractionCache, error) {
+	switch cfg.Backend {
+	case "memory":
+		return cache.NewMemoryCache(), nil
+	case "disk":
+		return cache.NewDiskCache(cfg.Dir)
+	default:
+		return nil, nil
+	}
+}
+
+func operationNames() []string {
+	names := make([]string, len(service.Operations))
+	for i, op := range service.Operations {
+		names[i] = string(op)
+	}
+	return names
 }
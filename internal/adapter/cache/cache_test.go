@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheTTL(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	caches := map[string]interface {
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	}{
+		"memory": NewMemoryCache(),
+		"disk":   disk,
+	}
+	ctx := context.Background()
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			for key, ttl := range map[string]time.Duration{"forever": 0, "hour": time.Hour, "expiring": 10 * time.Millisecond} {
+				if err := cache.Set(ctx, key, []byte(key), ttl); err != nil {
+					t.Fatal(err)
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			for key, want := range map[string]bool{"forever": true, "hour": true, "expiring": false, "missing": false} {
+				value, ok, err := cache.Get(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok != want || (ok && !bytes.Equal(value, []byte(key))) {
+					t.Errorf("Get(%q) = %q, %t, want found %t", key, value, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryCacheSweep(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	if err := c.Set(ctx, "expiring", []byte("old"), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	c.lastSweep = time.Now().Add(-sweepInterval)
+	if err := c.Set(ctx, "fresh", []byte("new"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.entries["expiring"]; ok || len(c.entries) != 1 {
+		t.Errorf("entries after sweep = %v, want only the fresh one", c.entries)
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-memory implementation of the ExtractionCache interface.
+// Expired entries are dropped when they are read, and swept at most once per sweepInterval
+// when an entry is set. It is safe for concurrent use.
+type MemoryCache struct {
+	mu        sync.RWMutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+const sweepInterval = time.Minute
+
+// NewMemoryCache creates and initializes a new in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if expired(entry.expiresAt) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastSweep) >= sweepInterval {
+		for k, entry := range c.entries {
+			if expired(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = time.Now()
+	}
+	c.entries[key] = memoryEntry{value: value, expiresAt: expiresAt(ttl)}
+	return nil
+}
+
+// expiresAt returns the zero time, meaning never, for a zero ttl.
+func expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
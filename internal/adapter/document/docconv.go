@@ -0,0 +1,260 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"code.sajari.com/docconv/v2"
+	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+const (
+	mimePDF   = "application/pdf"
+	mimeDOCX  = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimeDOC   = "application/msword"
+	mimeODT   = "application/vnd.oasis.opendocument.text"
+	mimeRTF   = "application/rtf"
+	mimeHTML  = "text/html"
+	mimePlain = "text/plain"
+	mimePNG   = "image/png"
+	mimeJPEG  = "image/jpeg"
+	mimeTIFF  = "image/tiff"
+)
+
+var supportedMimeTypes = map[string]bool{
+	mimePDF:   true,
+	mimeDOCX:  true,
+	mimeDOC:   true,
+	mimeODT:   true,
+	mimeRTF:   true,
+	mimeHTML:  true,
+	mimePlain: true,
+	mimePNG:   true,
+	mimeJPEG:  true,
+	mimeTIFF:  true,
+}
+
+var imageMimeTypes = map[string]bool{
+	mimePNG:  true,
+	mimeJPEG: true,
+	mimeTIFF: true,
+}
+
+var defaultOCRLanguages = []string{"fra", "eng"}
+
+const defaultOCRDPI = 300
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// DocconvExtractor extracts text with docconv, which relies on the pdftotext, wvText and unrtf
+// commands being installed for PDF, DOC and RTF files. Images and PDFs without a text layer are
+// read by OCR, which also needs pdftoppm and a build with the ocr tag.
+type DocconvExtractor struct {
+	languages     []string
+	minConfidence float64
+	dpi           int
+}
+
+func NewDocconvExtractor(cfg config.OCRConfig) *DocconvExtractor {
+	e := &DocconvExtractor{
+		languages:     cfg.Languages,
+		minConfidence: cfg.MinConfidence,
+		dpi:           cfg.DPI,
+	}
+	if len(e.languages) == 0 {
+		e.languages = defaultOCRLanguages
+	}
+	if e.dpi == 0 {
+		e.dpi = defaultOCRDPI
+	}
+	return e
+}
+
+// Extract converts file to clean text. Unsupported document types are rejected with
+// service.ErrInvalidArgument.
+func (e *DocconvExtractor) Extract(ctx context.Context, file model.File) (model.SourceDocument, error) {
+	mimeType := MimeType(file)
+	if !supportedMimeTypes[mimeType] {
+		return model.SourceDocument{}, fmt.Errorf("%w: unsupported document type %s", service.ErrInvalidArgument, mimeType)
+	}
+	source := model.SourceDocument{
+		FileName:  file.Name,
+		MimeType:  mimeType,
+		Extractor: "docconv",
+	}
+
+	var err error
+	switch {
+	case mimeType == mimePDF:
+		err = e.extractPDF(ctx, file.Content, &source)
+	case imageMimeTypes[mimeType]:
+		if !ocrAvailable {
+			return model.SourceDocument{}, fmt.Errorf("%w: images cannot be read without OCR, which this build does not include", service.ErrInvalidArgument)
+		}
+		err = e.recognizePages([][]byte{file.Content}, &source)
+	case mimeType == mimeHTML:
+		var text string
+		text, source.HiddenText, err = htmlToText(file.Content)
+		source.Extractor = "html"
+		source.Text = cleanText(text)
+	default:
+		var resp *docconv.Response
+		resp, err = docconv.Convert(bytes.NewReader(file.Content), mimeType, false)
+		if err == nil {
+			body := resp.Body
+			if mimeType == mimeDOCX {
+				hidden, hiddenErr := docxHiddenText(file.Content)
+				if hiddenErr != nil {
+					source.Warnings = append(source.Warnings, fmt.Sprintf("hidden text could not be searched for: %v", hiddenErr))
+				}
+				source.HiddenText = hidden.texts
+				body = hidden.strip(body, 0)
+			}
+			source.Text = cleanText(body)
+			source.Metadata = resp.Meta
+		}
+	}
+	if err != nil {
+		return model.SourceDocument{}, fmt.Errorf("failed to extract text from %s: %w", file.Name, err)
+	}
+	return source, nil
+}
+
+// extractPDF reads the text layer of a PDF, and falls back to OCR when it has none.
+func (e *DocconvExtractor) extractPDF(ctx context.Context, content []byte, source *model.SourceDocument) error {
+	path, err := writeTemp(content, "deckgen-*.pdf")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(path) }()
+
+	text, meta, err := pdfText(ctx, path)
+	if err != nil {
+		return err
+	}
+	source.Metadata = meta
+	if hasTextLayer(text) {
+		hidden, err := pdfHiddenText(ctx, path)
+		if err != nil {
+			source.Warnings = append(source.Warnings, fmt.Sprintf("hidden text could not be searched for: %v", err))
+		}
+		source.HiddenText = hidden.texts
+		pages := strings.Split(strings.TrimSuffix(text, "\f"), "\f")
+		for i := range pages {
+			pages[i] = hidden.strip(pages[i], i+1)
+		}
+		setPages(source, pages)
+		return nil
+	}
+	if !ocrAvailable {
+		source.Warnings = append(source.Warnings,
+			"the PDF has no text layer and OCR is not available in this build, so it can only be sent as a file, without removing hidden text")
+		return nil
+	}
+	pages, err := renderPDFPages(ctx, path, e.dpi)
+	if err != nil {
+		return err
+	}
+	return e.recognizePages(pages, source)
+}
+
+// recognizePages OCRs page images, recording the confidence of each page and a warning for every
+// page below the configured minimum confidence.
+func (e *DocconvExtractor) recognizePages(pages [][]byte, source *model.SourceDocument) error {
+	texts := make([]string, len(pages))
+	for i, page := range pages {
+		text, confidence, err := recognize(page, e.languages)
+		if err != nil {
+			return fmt.Errorf("OCR of page %d failed: %w", i+1, err)
+		}
+		texts[i] = text
+		ocrPage := model.OCRPage{
+			Page:       i + 1,
+			Confidence: math.Round(confidence*10) / 10,
+			LowQuality: e.minConfidence > 0 && confidence < e.minConfidence,
+		}
+		if ocrPage.LowQuality {
+			source.Warnings = append(source.Warnings, fmt.Sprintf(
+				"low OCR quality on page %d (confidence %.0f%%), the extracted text may be incomplete", ocrPage.Page, confidence))
+		}
+		source.OCRPages = append(source.OCRPages, ocrPage)
+	}
+	source.Extractor = "ocr"
+	setPages(source, texts)
+	return nil
+}
+
+// setPages joins the cleaned text of pages into source.Text, recording where each page starts.
+func setPages(source *model.SourceDocument, pages []string) {
+	var text strings.Builder
+	offset := 0
+	source.PageOffsets = make([]int, len(pages))
+	for i, page := range pages {
+		page = cleanText(page)
+		if text.Len() > 0 && page != "" {
+			text.WriteString("\n\n")
+			offset += 2
+		}
+		source.PageOffsets[i] = offset
+		text.WriteString(page)
+		offset += utf8.RuneCountInString(page)
+	}
+	source.Text = text.String()
+}
+
+// MimeType returns the declared type of file, guessed from its extension or else from its content.
+func MimeType(file model.File) string {
+	if mimeType := baseMimeType(file.MimeType); mimeType != "" && mimeType != "application/octet-stream" {
+		return mimeType
+	}
+	switch mimeType := docconv.MimeTypeByExtension("file." + file.Extension); mimeType {
+	case "application/octet-stream":
+	case "image/tif":
+		return mimeTIFF
+	default:
+		return mimeType
+	}
+	return baseMimeType(http.DetectContentType(file.Content))
+}
+
+func baseMimeType(value string) string {
+	mimeType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return ""
+	}
+	return mimeType
+}
+
+// cleanText normalizes line endings and whitespace, and drops control characters left by converters.
+func cleanText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == '\r' || r == '\f' || r == ' ':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		default:
+			return r
+		}
+	}, text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,482 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"golang.org/x/net/html"
+)
+
+const (
+	// minVisibleFontSize is the font size in points below which text is considered unreadable.
+	minVisibleFontSize = 4
+	// minWhiteLuminance is the luminance (0-1) above which a color is considered white. White text
+	// is hidden only on a background that is white too.
+	minWhiteLuminance = 0.93
+	// maxHiddenTextRunes bounds the length of each reported hidden text.
+	maxHiddenTextRunes = 300
+)
+
+const (
+	hiddenWhite   = "white text"
+	hiddenTiny    = "tiny text"
+	hiddenOffPage = "text outside the page"
+	hiddenVanish  = "text marked as hidden"
+)
+
+var xmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// pdfXML is the part of the output of pdftohtml -xml used to find hidden text.
+type pdfXML struct {
+	Pages []struct {
+		Number int     `xml:"number,attr"`
+		Width  float64 `xml:"width,attr"`
+		Height float64 `xml:"height,attr"`
+		Fonts  []struct {
+			ID    string  `xml:"id,attr"`
+			Size  float64 `xml:"size,attr"`
+			Color string  `xml:"color,attr"`
+		} `xml:"fontspec"`
+		Texts []struct {
+			Top    float64 `xml:"top,attr"`
+			Left   float64 `xml:"left,attr"`
+			Width  float64 `xml:"width,attr"`
+			Height float64 `xml:"height,attr"`
+			Font   string  `xml:"font,attr"`
+			Inner  string  `xml:",innerxml"`
+		} `xml:"text"`
+	} `xml:"page"`
+}
+
+// pdfHiddenText finds the white, tiny and off-page text of the PDF at path with pdftohtml. As
+// pdftohtml does not report backgrounds, the pages with white text are rendered to check that the
+// text really lies on a white area. Without a rendering, white text is reported anyway.
+func pdfHiddenText(ctx context.Context, path string) (hiddenTexts, error) {
+	out, err := exec.CommandContext(ctx, "pdftohtml", "-xml", "-i", "-q", "-zoom", "1", "-stdout", path).Output()
+	if err != nil {
+		return hiddenTexts{}, fmt.Errorf("pdftohtml failed: %w", err)
+	}
+	var doc pdfXML
+	decoder := xml.NewDecoder(bytes.NewReader(out))
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return hiddenTexts{}, fmt.Errorf("failed to read pdftohtml output: %w", err)
+	}
+
+	type font struct {
+		size  float64
+		color string
+	}
+	fonts := make(map[string]font)
+	var hidden hiddenTexts
+	var errs []error
+	for _, page := range doc.Pages {
+		for _, f := range page.Fonts {
+			fonts[f.ID] = font{size: f.Size, color: f.Color}
+		}
+		reasons := make([]string, len(page.Texts))
+		white := make([]bool, len(page.Texts))
+		hasWhite := false
+		for i, text := range page.Texts {
+			f := fonts[text.Font]
+			switch {
+			case f.size > 0 && f.size < minVisibleFontSize:
+				reasons[i] = hiddenTiny
+			case page.Width > 0 && (text.Left >= page.Width || text.Left+text.Width <= 0 ||
+				text.Top >= page.Height || text.Top+text.Height <= 0):
+				reasons[i] = hiddenOffPage
+			case isWhite(f.color):
+				white[i], hasWhite = true, true
+			}
+		}
+		if hasWhite {
+			// Coordinates are in points at zoom 1, and so are pixels at 72 dpi.
+			rendering, err := renderPDFPage(ctx, path, page.Number, 72)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("page %d could not be rendered to check the background of white text: %w", page.Number, err))
+			}
+			for i, text := range page.Texts {
+				if white[i] && (rendering == nil || lightArea(rendering, text.Left, text.Top, text.Width, text.Height)) {
+					reasons[i] = hiddenWhite
+				}
+			}
+		}
+		for i, text := range page.Texts {
+			hidden.add(xmlTextContent(text.Inner), reasons[i], page.Number)
+		}
+	}
+	return hidden, errors.Join(errs...)
+}
+
+// lightArea reports whether a box of a page is close to white on average, in which case white
+// text drawn in it cannot be seen.
+func lightArea(page image.Image, left, top, width, height float64) bool {
+	area := image.Rect(int(left), int(top), int(math.Ceil(left+width)), int(math.Ceil(top+height))).Intersect(page.Bounds())
+	if area.Empty() {
+		return true
+	}
+	var sum float64
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			r, g, b, _ := page.At(x, y).RGBA()
+			sum += luminance(float64(r>>8), float64(g>>8), float64(b>>8))
+		}
+	}
+	return sum/float64(area.Dx()*area.Dy()) > minWhiteLuminance
+}
+
+// docxRun is the formatting of a DOCX run that can hide it.
+type docxRun struct {
+	vanish bool
+	tiny   bool
+	color  string
+	// fill is the shading or highlight of the run.
+	fill string
+}
+
+// reason returns why the run is hidden, given the fills behind it from the innermost to the page.
+func (r docxRun) reason(fills ...string) string {
+	switch {
+	case r.vanish:
+		return hiddenVanish
+	case r.tiny:
+		return hiddenTiny
+	case isWhite(r.color) && lightBackground(append([]string{r.fill}, fills...)...):
+		return hiddenWhite
+	}
+	return ""
+}
+
+// docxHiddenText finds the runs of a DOCX document that are marked as hidden, tiny, or white on a
+// white background, taking the shading of runs, paragraphs and tables into account.
+func docxHiddenText(content []byte) (hiddenTexts, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return hiddenTexts{}, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	part, err := archive.Open("word/document.xml")
+	if err != nil {
+		return hiddenTexts{}, fmt.Errorf("failed to open DOCX body: %w", err)
+	}
+	defer part.Close()
+
+	var hidden hiddenTexts
+	var run docxRun
+	var paragraphFill, cellFill, tableFill, pageFill string
+	var elements []string
+	var inText bool
+	decoder := xml.NewDecoder(part)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return hiddenTexts{}, fmt.Errorf("failed to read DOCX body: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(elements) > 0 {
+				parent = elements[len(elements)-1]
+			}
+			elements = append(elements, t.Name.Local)
+			switch t.Name.Local {
+			case "r":
+				run = docxRun{}
+			case "p":
+				paragraphFill = ""
+			case "background":
+				pageFill = attr(t, "color")
+			case "vanish", "specVanish":
+				run.vanish = !isOff(attr(t, "val"))
+			case "color":
+				run.color = attr(t, "val")
+			case "sz":
+				// Sizes are in half-points.
+				if size, err := strconv.ParseFloat(attr(t, "val"), 64); err == nil && size/2 < minVisibleFontSize {
+					run.tiny = true
+				}
+			case "highlight":
+				run.fill = attr(t, "val")
+			case "shd":
+				switch parent {
+				case "rPr":
+					run.fill = attr(t, "fill")
+				case "pPr":
+					paragraphFill = attr(t, "fill")
+				case "tcPr":
+					cellFill = attr(t, "fill")
+				case "tblPr":
+					tableFill = attr(t, "fill")
+				}
+			case "t":
+				inText = true
+			}
+		case xml.EndElement:
+			if len(elements) > 0 {
+				elements = elements[:len(elements)-1]
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "tc":
+				cellFill = ""
+			case "tbl":
+				tableFill = ""
+			}
+		case xml.CharData:
+			if inText {
+				hidden.add(string(t), run.reason(paragraphFill, cellFill, tableFill, pageFill), 0)
+			}
+		}
+	}
+	return hidden, nil
+}
+
+// lightBackground reports whether the first color set among fills, given from the innermost to
+// the page, is close to white. A page without color is white.
+func lightBackground(fills ...string) bool {
+	for _, fill := range fills {
+		switch strings.ToLower(strings.TrimSpace(fill)) {
+		case "", "auto", "none", "transparent", "inherit", "initial":
+			continue
+		}
+		return isWhite(fill)
+	}
+	return true
+}
+
+// hiddenTexts merges consecutive hidden fragments with the same reason and page for the report,
+// and keeps them whole so that they can be stripped from the extracted text.
+type hiddenTexts struct {
+	texts []model.HiddenText
+	whole []string
+	open  bool
+}
+
+func (h *hiddenTexts) add(text, reason string, page int) {
+	if reason == "" {
+		h.open = false
+		return
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return
+	}
+	if n := len(h.texts); h.open && h.texts[n-1].Reason == reason && h.texts[n-1].Page == page {
+		h.whole[n-1] += " " + text
+		h.texts[n-1].Text = truncateRunes(h.whole[n-1], maxHiddenTextRunes)
+		return
+	}
+	h.texts = append(h.texts, model.HiddenText{Text: truncateRunes(text, maxHiddenTextRunes), Reason: reason, Page: page})
+	h.whole = append(h.whole, text)
+	h.open = true
+}
+
+// strip removes the hidden texts of page, 0 standing for a document without pages, from the
+// extracted text, so that no provider is sent what a reader does not see. Hidden texts are found
+// in document order, whatever the spacing between their words, which runs may split.
+func (h hiddenTexts) strip(text string, page int) string {
+	var b strings.Builder
+	rest := text
+	for i, hidden := range h.texts {
+		if hidden.Page != page {
+			continue
+		}
+		words := strings.Fields(h.whole[i])
+		for j, word := range words {
+			words[j] = regexp.QuoteMeta(word)
+		}
+		start, end, ok := findWords(rest, regexp.MustCompile(strings.Join(words, `\s*`)))
+		if !ok {
+			continue
+		}
+		b.WriteString(rest[:start])
+		rest = rest[end:]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
+
+// findWords returns the first match of pattern in text that neither starts nor ends inside a word.
+func findWords(text string, pattern *regexp.Regexp) (int, int, bool) {
+	for offset := 0; offset < len(text); {
+		loc := pattern.FindStringIndex(text[offset:])
+		if loc == nil {
+			return 0, 0, false
+		}
+		start, end := offset+loc[0], offset+loc[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return start, end, true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return 0, 0, false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// hiddenStyle returns why an inline CSS style hides an element drawn on a light or dark
+// background, or an empty string.
+func hiddenStyle(style string, onLight bool) string {
+	for _, declaration := range strings.Split(strings.ToLower(style), ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		switch strings.TrimSpace(property) {
+		case "display":
+			if value == "none" {
+				return hiddenVanish
+			}
+		case "visibility":
+			if value == "hidden" || value == "collapse" {
+				return hiddenVanish
+			}
+		case "opacity":
+			if opacity, err := strconv.ParseFloat(value, 64); err == nil && opacity < 0.1 {
+				return hiddenVanish
+			}
+		case "font-size":
+			if size, ok := cssPoints(value); ok && size < minVisibleFontSize {
+				return hiddenTiny
+			}
+		case "color":
+			if isWhite(value) && onLight {
+				return hiddenWhite
+			}
+		}
+	}
+	return ""
+}
+
+// styleBackground returns the background color set by an inline CSS style, or an empty string.
+// A background image counts as a color that is not white.
+func styleBackground(style string) string {
+	background := ""
+	for _, declaration := range strings.Split(strings.ToLower(style), ";") {
+		property, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		property = strings.TrimSpace(property)
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		switch property {
+		case "background-color":
+			background = value
+		case "background", "background-image":
+			switch {
+			case strings.Contains(value, "url(") || strings.Contains(value, "gradient("):
+				background = "image"
+			case property != "background":
+			case strings.HasPrefix(value, "rgb("):
+				background, _, _ = strings.Cut(value, ")")
+				background += ")"
+			case value != "":
+				background = strings.Fields(value)[0]
+			}
+		}
+	}
+	return background
+}
+
+// cssPoints converts a CSS length in px, pt or without unit to points.
+func cssPoints(value string) (float64, bool) {
+	factor := 1.0
+	switch {
+	case strings.HasSuffix(value, "px"):
+		value, factor = strings.TrimSuffix(value, "px"), 0.75
+	case strings.HasSuffix(value, "pt"):
+		value = strings.TrimSuffix(value, "pt")
+	}
+	size, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return size * factor, err == nil
+}
+
+// isWhite reports whether a color given as a name, #rgb, #rrggbb, rrggbb or rgb(r, g, b) is
+// close to white.
+func isWhite(color string) bool {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "white" {
+		return true
+	}
+	var r, g, b float64
+	if inner, ok := strings.CutPrefix(color, "rgb("); ok {
+		parts := strings.Split(strings.TrimSuffix(inner, ")"), ",")
+		if len(parts) != 3 {
+			return false
+		}
+		values := make([]float64, 3)
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return false
+			}
+			values[i] = value
+		}
+		r, g, b = values[0], values[1], values[2]
+	} else {
+		hex := strings.TrimPrefix(color, "#")
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 6 || err != nil {
+			return false
+		}
+		r, g, b = float64(value>>16), float64(value>>8&0xff), float64(value&0xff)
+	}
+	return luminance(r, g, b) > minWhiteLuminance
+}
+
+// luminance returns the relative luminance (0-1) of a color with 8-bit components.
+func luminance(r, g, b float64) float64 {
+	return (0.2126*r + 0.7152*g + 0.0722*b) / 255
+}
+
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func isOff(value string) bool {
+	return value == "0" || value == "false" || value == "off"
+}
+
+// xmlTextContent strips the formatting tags of an XML element content and decodes its entities.
+func xmlTextContent(inner string) string {
+	return html.UnescapeString(xmlTags.ReplaceAllString(inner, ""))
+}
+
+func truncateRunes(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	return string(runes[:maxRunes]) + "…"
+}
@@ -0,0 +1,135 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var skippedHTMLElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+}
+
+var blockHTMLElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Table: true, atom.Section: true, atom.Article: true,
+	atom.Header: true, atom.Footer: true, atom.Blockquote: true, atom.Pre: true, atom.Hr: true,
+}
+
+// htmlToText keeps the visible text of an HTML document, one block element per line, and returns
+// the text of the elements hidden by their attributes or inline style apart. It replaces docconv's
+// conversion, which returns nothing when the tidy command is not installed.
+func htmlToText(content []byte) (string, []model.HiddenText, error) {
+	root, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	var b strings.Builder
+	var hidden hiddenTexts
+	// light tells whether the background inherited by a node is white, as pages are by default.
+	var walk func(n *html.Node, light bool)
+	walk = func(n *html.Node, light bool) {
+		if n.Type == html.ElementNode && skippedHTMLElements[n.DataAtom] {
+			return
+		}
+		if background := elementBackground(n); background != "" {
+			light = isWhite(background)
+		}
+		if reason := hiddenElement(n, light); reason != "" {
+			hidden.add(nodeText(n), reason, 0)
+			hidden.add("", "", 0)
+			return
+		}
+		if n.Type == html.TextNode {
+			words := strings.Fields(n.Data)
+			if len(words) > 0 && unicode.IsSpace(rune(n.Data[0])) {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strings.Join(words, " "))
+			if len(words) > 0 && unicode.IsSpace(rune(n.Data[len(n.Data)-1])) {
+				b.WriteByte(' ')
+			}
+		}
+		block := n.Type == html.ElementNode && blockHTMLElements[n.DataAtom]
+		if block {
+			b.WriteByte('\n')
+		}
+		if n.DataAtom == atom.Li {
+			b.WriteString("- ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, light)
+		}
+		if block {
+			b.WriteByte('\n')
+		}
+	}
+	walk(root, true)
+	return b.String(), hidden.texts, nil
+}
+
+// hiddenElement returns why an element drawn on a light or dark background is not displayed, or
+// an empty string.
+func hiddenElement(n *html.Node, onLight bool) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return hiddenVanish
+		case "style":
+			if reason := hiddenStyle(a.Val, onLight); reason != "" {
+				return reason
+			}
+		}
+	}
+	return ""
+}
+
+// elementBackground returns the background color an element sets, or an empty string.
+func elementBackground(n *html.Node) string {
+	if n.Type != html.ElementNode {
+		return ""
+	}
+	background := ""
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "bgcolor":
+			if background == "" {
+				background = a.Val
+			}
+		case "style":
+			if color := styleBackground(a.Val); color != "" {
+				background = color
+			}
+		}
+	}
+	if lightBackground(background) && !isWhite(background) {
+		// Transparent and inherited backgrounds show the one of the parent.
+		return ""
+	}
+	return background
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var texts []string
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		texts = append(texts, nodeText(child))
+	}
+	return strings.Join(texts, " ")
+}
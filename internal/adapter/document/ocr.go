@@ -0,0 +1,40 @@
+//go:build ocr
+
+package document
+
+import (
+	"fmt"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+const ocrAvailable = true
+
+// recognize returns the text of an image along with the mean confidence (0-100) of its words.
+func recognize(image []byte, languages []string) (string, float64, error) {
+	client := gosseract.NewClient()
+	defer func() { _ = client.Close() }()
+
+	if err := client.SetLanguage(languages...); err != nil {
+		return "", 0, err
+	}
+	if err := client.SetImageFromBytes(image); err != nil {
+		return "", 0, fmt.Errorf("failed to load image: %w", err)
+	}
+	text, err := client.Text()
+	if err != nil {
+		return "", 0, fmt.Errorf("tesseract failed: %w", err)
+	}
+	words, err := client.GetBoundingBoxes(gosseract.RIL_WORD)
+	if err != nil {
+		return "", 0, fmt.Errorf("tesseract failed: %w", err)
+	}
+	if len(words) == 0 {
+		return text, 0, nil
+	}
+	var total float64
+	for _, word := range words {
+		total += word.Confidence
+	}
+	return text, total / float64(len(words)), nil
+}
@@ -0,0 +1,11 @@
+//go:build !ocr
+
+package document
+
+import "errors"
+
+const ocrAvailable = false
+
+func recognize([]byte, []string) (string, float64, error) {
+	return "", 0, errors.New("OCR requires a build with the ocr tag")
+}
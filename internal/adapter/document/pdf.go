@@ -0,0 +1,125 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// minTextLayerRunes is the number of letters and digits below which a PDF is considered scanned.
+const minTextLayerRunes = 32
+
+// pdfText runs pdftotext and pdfinfo on the PDF at path. Pages are separated by form feeds. docconv is not used for PDFs because,
+// when built with the ocr tag, it OCRs their images without reporting any confidence.
+func pdfText(ctx context.Context, path string) (string, map[string]string, error) {
+	body, err := exec.CommandContext(ctx, "pdftotext", "-q", "-enc", "UTF-8", "-eol", "unix", path, "-").Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("pdftotext failed: %w", err)
+	}
+	meta := make(map[string]string)
+	info, err := exec.CommandContext(ctx, "pdfinfo", path).Output()
+	if err != nil {
+		return string(body), meta, nil
+	}
+	for _, line := range strings.Split(string(info), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			meta[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return string(body), meta, nil
+}
+
+// hasTextLayer reports whether text extracted from a PDF holds more than stray characters.
+func hasTextLayer(text string) bool {
+	count := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			count++
+			if count >= minTextLayerRunes {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// renderPDFPages renders every page of the PDF at path to a PNG image with pdftoppm.
+func renderPDFPages(ctx context.Context, path string, dpi int) ([][]byte, error) {
+	dir, err := os.MkdirTemp("", "deckgen-pages-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cmd := exec.CommandContext(ctx, "pdftoppm", "-r", strconv.Itoa(dpi), "-png", path, filepath.Join(dir, "page"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	// pdftoppm pads page numbers to the same width, so names sort in page order.
+	sort.Strings(files)
+	pages := make([][]byte, len(files))
+	for i, file := range files {
+		if pages[i], err = os.ReadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// renderPDFPage renders a single page of the PDF at path with pdftoppm.
+func renderPDFPage(ctx context.Context, path string, page, dpi int) (image.Image, error) {
+	dir, err := os.MkdirTemp("", "deckgen-page-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	number := strconv.Itoa(page)
+	root := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, "pdftoppm", "-r", strconv.Itoa(dpi), "-f", number, "-l", number, "-singlefile", "-png", path, root)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	f, err := os.Open(root + ".png")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// writeTemp stores content in a temporary file for the commands that only read paths. The caller
+// must remove the returned file.
+func writeTemp(content []byte, pattern string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,250 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/rs/zerolog"
+)
+
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"
+	breakerOpen     breakerState = "open"
+	breakerHalfOpen breakerState = "half_open"
+)
+
+// circuitBreaker opens after too many consecutive failed or slow calls, lets a single probe
+// through once open_duration has elapsed, and closes again when that probe succeeds.
+type circuitBreaker struct {
+	name   service.LLMProviderName
+	cfg    config.BreakerConfig
+	logger zerolog.Logger
+
+	mu                  sync.Mutex
+	state               breakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+	lastLatency         time.Duration
+	lastError           string
+}
+
+func newCircuitBreaker(name service.LLMProviderName, cfg config.BreakerConfig, logger zerolog.Logger) *circuitBreaker {
+	return &circuitBreaker{
+		name:   name,
+		cfg:    cfg,
+		logger: logger.With().Str("provider", string(name)).Logger(),
+		state:  breakerClosed,
+	}
+}
+
+func (b *circuitBreaker) enabled() bool {
+	return b.cfg.FailureThreshold > 0
+}
+
+// available reports whether the provider should be selected at all.
+func (b *circuitBreaker) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		return time.Since(b.openedAt) >= b.cfg.OpenDuration
+	case breakerHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// allow reserves the right to make a call, moving an expired open breaker to half-open.
+func (b *circuitBreaker) allow() bool {
+	if !b.enabled() {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return false
+		}
+		b.transition(breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(err error, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	// A call that failed because of its caller, canceled or rejected as invalid, says nothing
+	// about the provider either way, so a probe is simply given back.
+	if err != nil && !countsAsFailure(err) {
+		return
+	}
+	b.lastLatency = latency
+
+	failed := err != nil
+	slow := b.cfg.SlowCallDuration > 0 && latency > b.cfg.SlowCallDuration
+	switch {
+	case failed:
+		b.lastError = err.Error()
+	case slow:
+		b.lastError = fmt.Sprintf("slow call: %s", latency)
+	}
+	if !b.enabled() {
+		return
+	}
+
+	if !failed && !slow {
+		b.consecutiveFailures = 0
+		if b.state != breakerClosed {
+			b.transition(breakerClosed)
+		}
+		return
+	}
+	b.consecutiveFailures++
+	if b.state == breakerHalfOpen || b.consecutiveFailures >= b.cfg.FailureThreshold {
+		b.openedAt = time.Now()
+		b.transition(breakerOpen)
+	}
+}
+
+// transition must be called with the lock held.
+func (b *circuitBreaker) transition(to breakerState) {
+	if b.state == to {
+		return
+	}
+	event := b.logger.Info()
+	if to == breakerOpen {
+		event = b.logger.Warn().Str("last_error", b.lastError)
+	}
+	event.Str("from", string(b.state)).Str("to", string(to)).
+		Int("consecutive_failures", b.consecutiveFailures).
+		Msg("circuit breaker state changed")
+	b.state = to
+}
+
+func (b *circuitBreaker) health() model.ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	health := model.ProviderHealth{
+		Provider:            string(b.name),
+		State:               string(b.state),
+		ConsecutiveFailures: b.consecutiveFailures,
+		OpenedAt:            b.openedAt,
+		LastError:           b.lastError,
+	}
+	if !b.enabled() {
+		health.State = "disabled"
+	}
+	if b.lastLatency > 0 {
+		health.LastLatency = b.lastLatency.String()
+	}
+	return health
+}
+
+// countsAsFailure ignores errors caused by the caller, which say nothing about provider health.
+func countsAsFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	kind, ok := service.LLMErrorKindOf(err)
+	if !ok {
+		return errors.Is(err, context.DeadlineExceeded)
+	}
+	return kind != service.LLMErrorInvalidRequest && kind != service.LLMErrorContentFiltered
+}
+
+// breakerProvider guards every call to the wrapped provider with its circuit breaker.
+type breakerProvider struct {
+	provider service.LLMProvider
+	breaker  *circuitBreaker
+}
+
+func guard[T any](ctx context.Context, b *circuitBreaker, call func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if !b.allow() {
+		return zero, fmt.Errorf("circuit breaker of provider %s is open: %w", b.name, service.ErrProviderUnavailable)
+	}
+	attempts := &attemptLatency{}
+	start := time.Now()
+	result, err := call(context.WithValue(ctx, attemptLatencyKey{}, attempts))
+	b.record(err, attempts.latency(time.Since(start)))
+	return result, err
+}
+
+// attemptLatency keeps the duration of the longest request made by a guarded call, so that the
+// slow call rule ignores the time spent waiting between retries.
+type attemptLatency struct {
+	mu       sync.Mutex
+	longest  time.Duration
+	observed bool
+}
+
+type attemptLatencyKey struct{}
+
+// observeAttempt records the duration of a single request to the provider.
+func observeAttempt(ctx context.Context, duration time.Duration) {
+	attempts, ok := ctx.Value(attemptLatencyKey{}).(*attemptLatency)
+	if !ok {
+		return
+	}
+	attempts.mu.Lock()
+	defer attempts.mu.Unlock()
+	attempts.longest = max(attempts.longest, duration)
+	attempts.observed = true
+}
+
+// latency returns the duration of the longest request, or elapsed when none was observed.
+func (a *attemptLatency) latency(elapsed time.Duration) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.observed {
+		return elapsed
+	}
+	return a.longest
+}
+
+func (p *breakerProvider) ModelName() string {
+	return p.provider.ModelName()
+}
+
+func (p *breakerProvider) PromptVersion(op service.Operation, locale string) string {
+	return p.provider.PromptVersion(op, locale)
+}
+
+func (p *breakerProvider) ParseResume(ctx context.Context, file model.File) (model.CandidateResume, error) {
+	return guard(ctx, p.breaker, func(ctx context.Context) (model.CandidateResume, error) {
+		return p.provider.ParseResume(ctx, file)
+	})
+}
+
+func (p *breakerProvider) ParseJobAd(ctx context.Context, file model.File) (model.JobAd, error) {
+	return guard(ctx, p.breaker, func(ctx context.Context) (model.JobAd, error) {
+		return p.provider.ParseJobAd(ctx, file)
+	})
+}
+
+func (p *breakerProvider) AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error) {
+	return guard(ctx, p.breaker, func(ctx context.Context) (model.ResumeAdaptation, error) {
+		return p.provider.AdaptResume(ctx, jobAd, resumes)
+	})
+}
@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/rs/zerolog"
+)
+
+func testBreaker(state breakerState, failures int) *circuitBreaker {
+	b := newCircuitBreaker("test", config.BreakerConfig{
+		FailureThreshold: 2,
+		SlowCallDuration: time.Second,
+		OpenDuration:     time.Minute,
+	}, zerolog.Nop())
+	b.state = state
+	b.consecutiveFailures = failures
+	if state == breakerHalfOpen {
+		b.probing = true
+	}
+	return b
+}
+
+func TestCircuitBreakerRecord(t *testing.T) {
+	retryable := &service.LLMError{Kind: service.LLMErrorRetryable, Err: errors.New("server error")}
+	invalidRequest := &service.LLMError{Kind: service.LLMErrorInvalidRequest, Err: errors.New("bad request")}
+	contentFiltered := &service.LLMError{Kind: service.LLMErrorContentFiltered, Err: errors.New("filtered")}
+	canceled := fmt.Errorf("call: %w", errors.Join(retryable, context.Canceled))
+
+	tests := []struct {
+		name         string
+		state        breakerState
+		failures     int
+		err          error
+		latency      time.Duration
+		wantState    breakerState
+		wantFailures int
+	}{
+		{"success resets failures", breakerClosed, 1, nil, time.Millisecond, breakerClosed, 0},
+		{"failure below threshold", breakerClosed, 0, retryable, time.Millisecond, breakerClosed, 1},
+		{"failure reaching threshold", breakerClosed, 1, retryable, time.Millisecond, breakerOpen, 2},
+		{"slow success counts as failure", breakerClosed, 1, nil, 2 * time.Second, breakerOpen, 2},
+		{"deadline exceeded counts as failure", breakerClosed, 0, context.DeadlineExceeded, time.Millisecond, breakerClosed, 1},
+		{"invalid request leaves failures", breakerClosed, 1, invalidRequest, time.Millisecond, breakerClosed, 1},
+		{"canceled call leaves failures", breakerClosed, 1, canceled, time.Millisecond, breakerClosed, 1},
+		{"probe success closes", breakerHalfOpen, 2, nil, time.Millisecond, breakerClosed, 0},
+		{"probe failure reopens", breakerHalfOpen, 2, retryable, time.Millisecond, breakerOpen, 3},
+		{"slow probe reopens", breakerHalfOpen, 2, nil, 2 * time.Second, breakerOpen, 3},
+		{"invalid request probe is given back", breakerHalfOpen, 2, invalidRequest, time.Millisecond, breakerHalfOpen, 2},
+		{"filtered probe is given back", breakerHalfOpen, 2, contentFiltered, time.Millisecond, breakerHalfOpen, 2},
+		{"canceled probe is given back", breakerHalfOpen, 2, canceled, time.Millisecond, breakerHalfOpen, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := testBreaker(tt.state, tt.failures)
+			b.record(tt.err, tt.latency)
+			if b.state != tt.wantState || b.consecutiveFailures != tt.wantFailures {
+				t.Errorf("state = %s with %d failures, want %s with %d", b.state, b.consecutiveFailures, tt.wantState, tt.wantFailures)
+			}
+			if b.probing {
+				t.Error("probe was not released")
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerAllow(t *testing.T) {
+	b := testBreaker(breakerOpen, 2)
+	b.openedAt = time.Now()
+	if b.allow() || b.available() {
+		t.Fatal("open breaker allowed a call before open_duration")
+	}
+
+	b.openedAt = time.Now().Add(-2 * time.Minute)
+	if !b.available() || !b.allow() {
+		t.Fatal("open breaker did not allow a probe after open_duration")
+	}
+	if b.state != breakerHalfOpen {
+		t.Fatalf("state = %s, want %s", b.state, breakerHalfOpen)
+	}
+	if b.allow() || b.available() {
+		t.Fatal("half-open breaker allowed a second probe")
+	}
+
+	b.record(nil, time.Millisecond)
+	if b.state != breakerClosed || !b.allow() {
+		t.Fatalf("state = %s after a successful probe, want %s", b.state, breakerClosed)
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker("test", config.BreakerConfig{}, zerolog.Nop())
+	for range 5 {
+		if !b.allow() {
+			t.Fatal("disabled breaker refused a call")
+		}
+		b.record(errors.New("failure"), time.Millisecond)
+	}
+	if health := b.health(); health.State != "disabled" {
+		t.Errorf("health state = %s, want disabled", health.State)
+	}
+}
+
+func TestGuardIgnoresRetryBackoff(t *testing.T) {
+	b := testBreaker(breakerClosed, 1)
+	b.cfg.SlowCallDuration = 50 * time.Millisecond
+	rp := retryPolicy{maxAttempts: 2}
+	_, err := guard(context.Background(), b, func(ctx context.Context) (string, error) {
+		attempt := 0
+		return "ok", rp.do(ctx, "test", func(ctx context.Context) error {
+			if attempt++; attempt == 1 {
+				return &service.LLMError{Kind: service.LLMErrorRateLimited, RetryAfter: 100 * time.Millisecond, Err: errors.New("rate limited")}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.state != breakerClosed || b.consecutiveFailures != 0 {
+		t.Errorf("state = %s with %d failures after a retried fast call, want closed with 0", b.state, b.consecutiveFailures)
+	}
+}
@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/responses"
+)
+
+const openAIProviderName service.LLMProviderName = "openai"
+
+// classifyOpenAIError wraps an error returned by the OpenAI client into a *service.LLMError.
+// Cancellation of the caller's context is returned as is so that it is never retried.
+func classifyOpenAIError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+	llmErr := &service.LLMError{Provider: openAIProviderName, Kind: service.LLMErrorRetryable, Err: err}
+
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		llmErr.StatusCode = apiErr.StatusCode
+		llmErr.Kind = kindFromStatus(apiErr.StatusCode, apiErr.Code)
+		if apiErr.Response != nil {
+			llmErr.RetryAfter = parseRetryAfter(apiErr.Response.Header)
+		}
+		return llmErr
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, context.DeadlineExceeded):
+		llmErr.Kind = service.LLMErrorRetryable
+	default:
+		llmErr.Kind = service.LLMErrorInvalidRequest
+	}
+	return llmErr
+}
+
+func kindFromStatus(status int, code string) service.LLMErrorKind {
+	if code == "content_policy_violation" || code == "content_filter" {
+		return service.LLMErrorContentFiltered
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		return service.LLMErrorRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return service.LLMErrorAuth
+	case status == http.StatusRequestTimeout || status == http.StatusConflict || status >= http.StatusInternalServerError:
+		return service.LLMErrorRetryable
+	default:
+		return service.LLMErrorInvalidRequest
+	}
+}
+
+// checkResponseStatus classifies responses that were accepted by the API but did not complete.
+func checkResponseStatus(resp *responses.Response) error {
+	switch resp.Status {
+	case responses.ResponseStatusFailed:
+		err := fmt.Errorf("response %s failed: %s: %s", resp.ID, resp.Error.Code, resp.Error.Message)
+		kind := service.LLMErrorInvalidRequest
+		switch resp.Error.Code {
+		case responses.ResponseErrorCodeServerError:
+			kind = service.LLMErrorRetryable
+		case responses.ResponseErrorCodeRateLimitExceeded:
+			kind = service.LLMErrorRateLimited
+		case responses.ResponseErrorCodeImageContentPolicyViolation:
+			kind = service.LLMErrorContentFiltered
+		}
+		return &service.LLMError{Provider: openAIProviderName, Kind: kind, Err: err}
+	case responses.ResponseStatusIncomplete:
+		err := fmt.Errorf("response %s is incomplete: %s", resp.ID, resp.IncompleteDetails.Reason)
+		kind := service.LLMErrorInvalidRequest
+		if resp.IncompleteDetails.Reason == "content_filter" {
+			kind = service.LLMErrorContentFiltered
+		}
+		return &service.LLMError{Provider: openAIProviderName, Kind: kind, Err: err}
+	}
+	return nil
+}
+
+// parseRetryAfter reads the Retry-After-Ms or Retry-After headers, the latter being either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
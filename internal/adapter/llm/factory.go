@@ -1,42 +1,130 @@
 package llm
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/prompt"
 	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/rs/zerolog"
 )
 
 type LLMFactory struct {
-	providers map[service.LLMProviderName]service.LLMProvider
+	providers map[service.LLMProviderName]*breakerProvider
+	sweepers  []fileSweeperJob
+	logger    zerolog.Logger
 }
 
-func NewLLMFactory(cfg map[string]config.LLMProviderConfig) (*LLMFactory, error) {
-	providers := make(map[service.LLMProviderName]service.LLMProvider)
+// fileSweeper is implemented by providers that upload documents and can delete the leftovers.
+type fileSweeper interface {
+	SweepFiles(ctx context.Context, olderThan time.Duration) (int, error)
+}
+
+type fileSweeperJob struct {
+	provider service.LLMProviderName
+	sweeper  fileSweeper
+	cfg      config.FileSweeperConfig
+}
+
+func NewLLMFactory(cfg map[string]config.LLMProviderConfig, prompts *prompt.Registry, logger zerolog.Logger) (*LLMFactory, error) {
+	factory := &LLMFactory{
+		providers: make(map[service.LLMProviderName]*breakerProvider),
+		logger:    logger,
+	}
 	for name, providerCfg := range cfg {
 		if !providerCfg.Enabled {
 			continue
 		}
 
+		var provider service.LLMProvider
 		switch service.LLMProviderName(name) {
-		case "openai":
-			provider, err := NewOpenAIProvider(providerCfg)
+		case openAIProviderName:
+			openAIProvider, err := NewOpenAIProvider(providerCfg, prompts)
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize %s provider: %w", name, err)
 			}
-			providers[service.LLMProviderName(name)] = provider
+			provider = openAIProvider
 		default:
 			return nil, fmt.Errorf("failed to initialize %s provider: %s", name, "provider is not supported")
 		}
 
+		providerName := service.LLMProviderName(name)
+		factory.providers[providerName] = &breakerProvider{
+			provider: provider,
+			breaker:  newCircuitBreaker(providerName, providerCfg.Breaker, logger),
+		}
+		if sweeper, ok := provider.(fileSweeper); ok && providerCfg.FileSweeper.Interval > 0 {
+			factory.sweepers = append(factory.sweepers, fileSweeperJob{
+				provider: providerName,
+				sweeper:  sweeper,
+				cfg:      providerCfg.FileSweeper,
+			})
+		}
 	}
-	return &LLMFactory{providers: providers}, nil
+	return factory, nil
 }
 
+// GetProvider returns the named provider, unless its circuit breaker is currently open.
 func (f *LLMFactory) GetProvider(providerType service.LLMProviderName) (service.LLMProvider, error) {
+	provider, ok := f.providers[providerType]
+	if !ok {
+		return nil, fmt.Errorf("provider '%s' is not supported or not enabled in config", providerType)
+	}
+	if !provider.breaker.available() {
+		return nil, fmt.Errorf("provider '%s' is skipped until its circuit breaker closes: %w", providerType, service.ErrProviderUnavailable)
+	}
+	return provider, nil
+}
+
+// DescribeProvider returns an enabled provider even while its circuit breaker is open, for
+// callers that only read its model and prompt versions.
+func (f *LLMFactory) DescribeProvider(providerType service.LLMProviderName) (service.LLMProvider, error) {
 	provider, ok := f.providers[providerType]
 	if !ok {
 		return nil, fmt.Errorf("provider '%s' is not supported or not enabled in config", providerType)
 	}
 	return provider, nil
 }
+
+// ProviderHealth returns the circuit breaker state of every enabled provider.
+func (f *LLMFactory) ProviderHealth() []model.ProviderHealth {
+	health := make([]model.ProviderHealth, 0, len(f.providers))
+	for _, provider := range f.providers {
+		health = append(health, provider.breaker.health())
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Provider < health[j].Provider
+	})
+	return health
+}
+
+// StartFileSweepers periodically deletes the files that providers uploaded and failed to delete,
+// until ctx is done.
+func (f *LLMFactory) StartFileSweepers(ctx context.Context) {
+	for _, job := range f.sweepers {
+		go f.runFileSweeper(ctx, job)
+	}
+}
+
+func (f *LLMFactory) runFileSweeper(ctx context.Context, job fileSweeperJob) {
+	logger := f.logger.With().Str("provider", string(job.provider)).Logger()
+	ticker := time.NewTicker(job.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := job.sweeper.SweepFiles(ctx, job.cfg.MaxAge)
+		if err != nil && ctx.Err() == nil {
+			logger.Warn().Err(err).Int("deleted", deleted).Msg("failed to sweep uploaded files")
+		} else if deleted > 0 {
+			logger.Info().Int("deleted", deleted).Msg("deleted orphaned uploaded files")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
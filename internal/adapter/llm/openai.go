@@ -3,229 +3,253 @@ package llm
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mfreyr/deckgen/internal/config"
 	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/prompt"
+	"github.com/mfreyr/deckgen/internal/service"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/responses"
 )
 
+// documentPromptData holds the variables of the parse_resume and parse_job_ad prompts. Document is
+// empty when the document is attached as a file.
+type documentPromptData struct {
+	Document string
+	Part     int
+	Parts    int
+}
+
+// adaptPromptData holds the variables of the adapt_resume prompt.
+type adaptPromptData struct {
+	JobAd   string
+	Resumes []string
+}
+
 const (
-	parseResumePromptTemplate = `
-		**Objective:**
-		Analyze the provided raw text from a resume file.
-		Extract the information and structure it into a valid JSON object that adheres exactly to the provided JSON schema.
-
-		**Instructions:**
-		1. Parse the document to identify key sections like professional summary, work experience, skills, and certifications.
-		2. Populate all fields of the JSON schema as accurately as possible.
-		3. The output MUST be a single, valid JSON object. Do not include any text, markdown, or commentary outside of the JSON object.
-
-		**Input Data (Raw Text from Resume):**
-		---
-		%s
-	`
-	parseJobAdPromptTemplate = `
-		**Objective:**
-		Analyze the provided raw text from a job advertisement.
-		Extract the information and structure it into a valid JSON object that adheres exactly to the provided JSON schema.
-
-		**Instructions:**
-		1. Parse the document to identify key sections like job title, company name, responsibilities, and qualifications.
-		2. Populate all fields of the JSON schema as accurately as possible.
-		3. The output MUST be a single, valid JSON object. Do not include any text, markdown, or commentary outside of the JSON object.
-
-		**Input Data (Raw Text from Job Ad):**
-		---
-		%s
-	`
-	adaptResumePromptTemplate = `
-		**Objective:**
-		Analyze the provided Job Advertisement and one or more candidate resumes.
-		Generate a new, adapted resume in JSON format that highlights the candidate's most relevant skills and experiences for this specific job.
-
-		**Instructions:**
-		1.  Carefully read the Job Advertisement to understand the key requirements, skills, and responsibilities.
-		2.  Thoroughly review all provided candidate resumes to understand the candidate's background, skills, and accomplishments.
-		3.  Synthesize this information to create compelling, concise, and action-oriented content for a new, adapted resume.
-		4.  The output MUST be a single, valid JSON object that adheres exactly to the schema provided for the adapted resume.
-
-		**Input Data:**
-
-		--- Job Advertisement ---
-		%s
-
-		--- Candidate Resumes ---
-		%s
-	`
+	pdfMimeType = "application/pdf"
+	// uploadPrefix marks the files uploaded by deckgen. SweepFiles only deletes the ones that also
+	// carry the instance of the provider.
+	uploadPrefix = "deckgen-"
+	// fileDeleteTimeout bounds the deletion of an uploaded file after its response.
+	fileDeleteTimeout = 10 * time.Second
 )
 
+// delimiterTags matches the tags enclosing documents, job ads and resumes in prompts.
+var delimiterTags = regexp.MustCompile(`(?i)<\s*/?\s*(?:document|job_ad|resume)\b[^>]*>`)
+
+// repairPromptName is the prompt sent back to the model along with the problems of its output.
+const repairPromptName = "repair_output"
+
+// repairPromptData holds the variables of the repair_output prompt.
+type repairPromptData struct {
+	Problems []string
+}
+
 // OpenAIProvider implements the service.LLMProvider interface for OpenAI's models.
 type OpenAIProvider struct {
-	client    *openai.Client
-	modelName string
+	client     *openai.Client
+	modelName  string
+	retry      retryPolicy
+	maxRepairs int
+	prompts    *prompt.Registry
+	// instancePrefix starts the names of the files uploaded by this deployment.
+	instancePrefix string
 }
 
 // NewOpenAIProvider initializes and returns a new OpenAIProvider using the given config.
-func NewOpenAIProvider(cfg config.LLMProviderConfig) (*OpenAIProvider, error) {
+func NewOpenAIProvider(cfg config.LLMProviderConfig, prompts *prompt.Registry) (*OpenAIProvider, error) {
 	if cfg.APIKey == "" {
 		return nil, fmt.Errorf("openAI API key is required")
 	}
 	if cfg.Model == "" {
 		return nil, fmt.Errorf("openAI model name is required in config")
 	}
+	if err := prompts.Require(repairPromptName); err != nil {
+		return nil, err
+	}
 
-	client := openai.NewClient(option.WithAPIKey(cfg.APIKey))
+	// Retries are handled by our own policy so that errors can be classified and budgeted.
+	client := openai.NewClient(option.WithAPIKey(cfg.APIKey), option.WithMaxRetries(0))
+
+	instancePrefix := uploadPrefix
+	if cfg.FileSweeper.Instance != "" {
+		instancePrefix += cfg.FileSweeper.Instance + "-"
+	}
 
 	return &OpenAIProvider{
-		client:    &client,
-		modelName: cfg.Model,
+		client:         &client,
+		modelName:      cfg.Model,
+		retry:          newRetryPolicy(cfg.Retry),
+		maxRepairs:     cfg.MaxRepairs,
+		prompts:        prompts,
+		instancePrefix: instancePrefix,
 	}, nil
 }
 
-// ParseResume uses an LLM to parse a file into a structured CandidateResume.
-func (p *OpenAIProvider) ParseResume(ctx context.Context, file model.File) (model.CandidateResume, error) {
-	var resume model.CandidateResume
-	prompt := fmt.Sprintf(parseResumePromptTemplate, string(file.Content))
+// ModelName returns the OpenAI model used for every request.
+func (p *OpenAIProvider) ModelName() string {
+	return p.modelName
+}
 
-	fileParam := openai.FileNewParams{
-		File:    openai.File(bytes.NewReader(file.Content), "resume.pdf", "application/pdf"),
-		Purpose: openai.FilePurposeUserData,
+// PromptVersion returns the identifier of the prompt used for an operation in the given locale.
+func (p *OpenAIProvider) PromptVersion(op service.Operation, locale string) string {
+	tmpl, err := p.prompts.Get(string(op), locale)
+	if err != nil {
+		return ""
 	}
-	storedFile, err := p.client.Files.New(ctx, fileParam)
+	return tmpl.ID()
+}
+
+// ParseResume uses an LLM to parse a file into a structured CandidateResume.
+func (p *OpenAIProvider) ParseResume(ctx context.Context, file model.File) (model.CandidateResume, error) {
+	input, fileID, err := p.documentInput(ctx, service.OperationParseResume, file)
 	if err != nil {
-		return model.CandidateResume{}, fmt.Errorf("error uploading file to OpenAI: %w", err)
+		return model.CandidateResume{}, err
 	}
+	defer p.deleteFile(ctx, fileID)
 
 	params := responses.ResponseNewParams{
 		Model: openai.ChatModel(p.modelName),
-		Text: responses.ResponseTextConfigParam{
-			Format: responses.ResponseFormatTextConfigUnionParam{
-				OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
-					Name:        "Parsed Resume",
-					Description: openai.String("Structured json resume parsed from a file"),
-					Schema:      GenerateSchema[model.CandidateResume](),
-					Strict:      openai.Bool(true),
-				},
-			},
-		},
-		Input: responses.ResponseNewParamsInputUnion{
-			OfInputItemList: responses.ResponseInputParam{
-				responses.ResponseInputItemParamOfMessage(
-					responses.ResponseInputMessageContentListParam{
-						responses.ResponseInputContentUnionParam{
-							OfInputFile: &responses.ResponseInputFileParam{
-								FileID: openai.String(storedFile.ID),
-							},
-						},
-						responses.ResponseInputContentUnionParam{
-							OfInputText: &responses.ResponseInputTextParam{
-								Text: prompt,
-							},
-						},
-					},
-					"user",
-				),
-			},
-		},
+		Input: input,
+	}
+	check := model.CheckResume
+	if file.Parts > 0 {
+		check = model.CheckResumePart
 	}
+	return requestStructured(ctx, p, params, "Parsed Resume", "Structured json resume parsed from a file", check)
+}
 
-	rawJSON, err := p.executeRequest(ctx, params)
+// ParseJobAd uses an LLM to parse a file into a structured JobAd.
+func (p *OpenAIProvider) ParseJobAd(ctx context.Context, file model.File) (model.JobAd, error) {
+	input, fileID, err := p.documentInput(ctx, service.OperationParseJobAd, file)
 	if err != nil {
-		return resume, err
+		return model.JobAd{}, err
 	}
+	defer p.deleteFile(ctx, fileID)
 
-	if err := json.Unmarshal([]byte(rawJSON), &resume); err != nil {
-		log.Printf("Failed to unmarshal JSON from OpenAI for ParseResume. Raw response:\n%s", rawJSON)
-		return resume, fmt.Errorf("failed to unmarshal JSON from OpenAI: %w", err)
+	params := responses.ResponseNewParams{
+		Model: openai.ChatModel(p.modelName),
+		Input: input,
 	}
-
-	return resume, nil
+	check := model.CheckJobAd
+	if file.Parts > 0 {
+		check = nil
+	}
+	return requestStructured(ctx, p, params, "Parsed Job Ad", "Structured json of job ad parsed from a file", check)
 }
 
-// ParseJobAd uses an LLM to parse a file into a structured JobAd.
-func (p *OpenAIProvider) ParseJobAd(ctx context.Context, file model.File) (model.JobAd, error) {
-	var jobAd model.JobAd
-	prompt := fmt.Sprintf(parseJobAdPromptTemplate, string(file.Content))
+// documentInput renders the prompt of op for file. The extracted text of the document is
+// embedded in the prompt whenever there is one. Otherwise the document must be a PDF, which is
+// uploaded and attached as a file input, the ID of the uploaded file being returned so that it can
+// be deleted after use. Only embedded text is stripped of hidden text and enclosed in delimiters:
+// an uploaded file reaches the model as it is.
+func (p *OpenAIProvider) documentInput(ctx context.Context, op service.Operation, file model.File) (responses.ResponseNewParamsInputUnion, string, error) {
+	locale := service.LocaleFromContext(ctx)
+	if strings.TrimSpace(file.Text) != "" {
+		prompt, _, err := p.prompts.Render(string(op), locale, documentPromptData{
+			Document: escapeDelimiters(file.Text),
+			Part:     file.Part,
+			Parts:    file.Parts,
+		})
+		if err != nil {
+			return responses.ResponseNewParamsInputUnion{}, "", err
+		}
+		return responses.ResponseNewParamsInputUnion{OfString: openai.String(prompt)}, "", nil
+	}
 
-	fileParam := openai.FileNewParams{
-		File:    openai.File(bytes.NewReader(file.Content), "job_ad.pdf", "application/pdf"),
-		Purpose: openai.FilePurposeUserData,
+	// The content type sent by the client is not trusted: only what is really a PDF is uploaded.
+	if contentType := http.DetectContentType(file.Content); contentType != pdfMimeType {
+		return responses.ResponseNewParamsInputUnion{}, "", fmt.Errorf("%w: no text could be extracted from %s, which is not a PDF but %s",
+			service.ErrInvalidArgument, file.Name, contentType)
 	}
-	storedFile, err := p.client.Files.New(ctx, fileParam)
+	prompt, _, err := p.prompts.Render(string(op), locale, documentPromptData{})
 	if err != nil {
-		return model.JobAd{}, fmt.Errorf("error uploading file to OpenAI: %w", err)
+		return responses.ResponseNewParamsInputUnion{}, "", err
 	}
-
-	params := responses.ResponseNewParams{
-		Model: openai.ChatModel(p.modelName),
-		Text: responses.ResponseTextConfigParam{
-			Format: responses.ResponseFormatTextConfigUnionParam{
-				OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
-					Name:        "Parsed Job Ad",
-					Description: openai.String("Structured json of job ad parsed from a file"),
-					Schema:      GenerateSchema[model.CandidateResume](),
-					Strict:      openai.Bool(true),
-				},
-			},
-		},
-		Input: responses.ResponseNewParamsInputUnion{
-			OfInputItemList: responses.ResponseInputParam{
-				responses.ResponseInputItemParamOfMessage(
-					responses.ResponseInputMessageContentListParam{
-						responses.ResponseInputContentUnionParam{
-							OfInputFile: &responses.ResponseInputFileParam{
-								FileID: openai.String(storedFile.ID),
-							},
+	storedFile, err := p.uploadFile(ctx, file.Content, p.uploadName(file.Content), pdfMimeType)
+	if err != nil {
+		return responses.ResponseNewParamsInputUnion{}, "", err
+	}
+	return responses.ResponseNewParamsInputUnion{
+		OfInputItemList: responses.ResponseInputParam{
+			responses.ResponseInputItemParamOfMessage(
+				responses.ResponseInputMessageContentListParam{
+					responses.ResponseInputContentUnionParam{
+						OfInputFile: &responses.ResponseInputFileParam{
+							FileID: openai.String(storedFile.ID),
 						},
-						responses.ResponseInputContentUnionParam{
-							OfInputText: &responses.ResponseInputTextParam{
-								Text: prompt,
-							},
+					},
+					responses.ResponseInputContentUnionParam{
+						OfInputText: &responses.ResponseInputTextParam{
+							Text: prompt,
 						},
 					},
-					"user",
-				),
-			},
+				},
+				"user",
+			),
 		},
-	}
-	rawJSON, err := p.executeRequest(ctx, params)
-	if err != nil {
-		return jobAd, err
-	}
+	}, storedFile.ID, nil
+}
 
-	if err := json.Unmarshal([]byte(rawJSON), &jobAd); err != nil {
-		log.Printf("Failed to unmarshal JSON from OpenAI for ParseJobAd. Raw response:\n%s", rawJSON)
-		return jobAd, fmt.Errorf("failed to unmarshal JSON from OpenAI: %w", err)
-	}
+// uploadName names an uploaded PDF after its content rather than after the original file, whose
+// name is often the one of the candidate, and starts it with the prefix the sweeper looks for.
+func (p *OpenAIProvider) uploadName(content []byte) string {
+	sum := sha256.Sum256(content)
+	return p.instancePrefix + hex.EncodeToString(sum[:8]) + ".pdf"
+}
 
-	return jobAd, nil
+// escapeDelimiters neutralizes the tags prompts use to enclose untrusted content, so that a
+// document cannot close its enclosing tag and pass text off as instructions. JSON inputs need no
+// escaping, as json.Marshal already escapes angle brackets.
+func escapeDelimiters(text string) string {
+	return delimiterTags.ReplaceAllStringFunc(text, func(tag string) string {
+		return "‹" + strings.TrimSuffix(tag[1:], ">") + "›"
+	})
 }
 
 // AdaptResume uses an LLM to tailor existing resumes for a specific job ad.
-func (p *OpenAIProvider) AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.CandidateAdaptedResume, error) {
-	var adaptedResume model.CandidateAdaptedResume
+func (p *OpenAIProvider) AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error) {
+	var adaptation model.ResumeAdaptation
 
-	var resumeBuilder strings.Builder
+	data := adaptPromptData{Resumes: make([]string, len(resumes))}
 	for i, resume := range resumes {
+		// The source text is already summarized by the parsed fields.
+		resume.Source = nil
+		resume.Provenance = nil
+		resume.Suspicious = nil
 		resumeBytes, err := json.Marshal(resume)
 		if err != nil {
-			return adaptedResume, fmt.Errorf("failed to marshal resume ID %d to JSON: %w", resume.ID, err)
+			return adaptation, fmt.Errorf("failed to marshal resume ID %d to JSON: %w", resume.ID, err)
 		}
-		resumeBuilder.WriteString(fmt.Sprintf("\n--- Candidate Resume %d ---\n%s", i+1, string(resumeBytes)))
+		data.Resumes[i] = string(resumeBytes)
 	}
 
+	jobAd.Source = nil
+	jobAd.Provenance = nil
+	jobAd.Suspicious = nil
 	jobAdBytes, err := json.Marshal(jobAd)
 	if err != nil {
-		return adaptedResume, fmt.Errorf("failed to marshal job ad to JSON: %w", err)
+		return adaptation, fmt.Errorf("failed to marshal job ad to JSON: %w", err)
 	}
+	data.JobAd = string(jobAdBytes)
 
-	prompt := fmt.Sprintf(adaptResumePromptTemplate, string(jobAdBytes), resumeBuilder.String())
+	prompt, _, err := p.prompts.Render(string(service.OperationAdaptResume), service.LocaleFromContext(ctx), data)
+	if err != nil {
+		return adaptation, err
+	}
 
 	params := responses.ResponseNewParams{
 		Model: openai.ChatModel(p.modelName),
@@ -233,22 +257,159 @@ func (p *OpenAIProvider) AdaptResume(ctx context.Context, jobAd model.JobAd, res
 			OfString: openai.String(prompt),
 		},
 	}
-	rawJSON, err := p.executeRequest(ctx, params)
+	return requestStructured(ctx, p, params, "Resume Adaptation", "Resume tailored to a job ad with the rationale of each section", checkAdaptation)
+}
+
+// requestStructured requests an output following the strict JSON schema of T, then validates it
+// against that schema and the rules of check. Invalid outputs are sent back to the model along
+// with their problems, up to maxRepairs times, before giving up with an invalid_output error.
+func requestStructured[T any](
+	ctx context.Context,
+	p *OpenAIProvider,
+	params responses.ResponseNewParams,
+	name, description string,
+	check func(T) []string,
+) (T, error) {
+	var zero T
+	schema := GenerateSchema[T]()
+	params.Text = responses.ResponseTextConfigParam{
+		Format: responses.ResponseFormatTextConfigUnionParam{
+			OfJSONSchema: &responses.ResponseFormatTextJSONSchemaConfigParam{
+				Name:        name,
+				Description: openai.String(description),
+				Schema:      schema,
+				Strict:      openai.Bool(true),
+			},
+		},
+	}
+
+	for attempt := 0; ; attempt++ {
+		rawJSON, err := p.executeRequest(ctx, params)
+		if err != nil {
+			return zero, err
+		}
+		result, problems := decodeOutput(rawJSON, schema, check)
+		if len(problems) == 0 {
+			return result, nil
+		}
+		if attempt >= p.maxRepairs {
+			log.Printf("Invalid output from OpenAI for %s. Raw response:\n%s", name, rawJSON)
+			return zero, &service.LLMError{
+				Provider: openAIProviderName,
+				Kind:     service.LLMErrorInvalidOutput,
+				Attempts: attempt + 1,
+				Err:      fmt.Errorf("%s output is invalid: %s", name, strings.Join(problems, "; ")),
+			}
+		}
+		log.Printf("Asking OpenAI to repair %d problem(s) in %s output (repair %d/%d)", len(problems), name, attempt+1, p.maxRepairs)
+		params.Input, err = p.repairInput(ctx, params.Input, rawJSON, problems)
+		if err != nil {
+			return zero, err
+		}
+	}
+}
+
+// repairInput continues the conversation with the invalid output and the problems found in it.
+func (p *OpenAIProvider) repairInput(
+	ctx context.Context,
+	input responses.ResponseNewParamsInputUnion,
+	output string,
+	problems []string,
+) (responses.ResponseNewParamsInputUnion, error) {
+	repairPrompt, _, err := p.prompts.Render(repairPromptName, service.LocaleFromContext(ctx), repairPromptData{Problems: problems})
 	if err != nil {
-		return adaptedResume, err
+		return input, err
 	}
+	items := slices.Clone(input.OfInputItemList)
+	if input.OfString.Valid() {
+		items = responses.ResponseInputParam{
+			responses.ResponseInputItemParamOfMessage(input.OfString.Value, responses.EasyInputMessageRoleUser),
+		}
+	}
+	items = append(items,
+		responses.ResponseInputItemParamOfMessage(output, responses.EasyInputMessageRoleAssistant),
+		responses.ResponseInputItemParamOfMessage(repairPrompt, responses.EasyInputMessageRoleUser),
+	)
+	return responses.ResponseNewParamsInputUnion{OfInputItemList: items}, nil
+}
 
-	if err := json.Unmarshal([]byte(rawJSON), &adaptedResume); err != nil {
-		log.Printf("Failed to unmarshal JSON from OpenAI for AdaptResume. Raw response:\n%s", rawJSON)
-		return adaptedResume, fmt.Errorf("failed to unmarshal JSON from OpenAI: %w", err)
+// uploadFile stores the content on OpenAI so that it can be referenced as a file input.
+func (p *OpenAIProvider) uploadFile(ctx context.Context, content []byte, name, contentType string) (*openai.FileObject, error) {
+	var storedFile *openai.FileObject
+	err := p.retry.do(ctx, "openai file upload", func(ctx context.Context) error {
+		fileParam := openai.FileNewParams{
+			File:    openai.File(bytes.NewReader(content), name, contentType),
+			Purpose: openai.FilePurposeUserData,
+		}
+		var err error
+		storedFile, err = p.client.Files.New(ctx, fileParam)
+		return classifyOpenAIError(err)
+	})
+	if err == nil {
+		service.RecordUsage(ctx, model.UsageRecord{Model: p.modelName, UploadedBytes: storedFile.Bytes})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error uploading file to OpenAI: %w", err)
 	}
+	return storedFile, nil
+}
 
-	return adaptedResume, nil
+// deleteFile removes an uploaded file once its response is received, even if ctx is canceled.
+// Files that cannot be deleted are left to SweepFiles.
+func (p *OpenAIProvider) deleteFile(ctx context.Context, fileID string) {
+	if fileID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fileDeleteTimeout)
+	defer cancel()
+	if _, err := p.client.Files.Delete(ctx, fileID); err != nil {
+		log.Printf("Failed to delete OpenAI file %s, leaving it to the sweeper: %v", fileID, err)
+	}
+}
+
+// SweepFiles deletes the files uploaded by this instance more than olderThan ago, which were
+// orphaned by a failed deletion or a crash, and returns how many were deleted.
+func (p *OpenAIProvider) SweepFiles(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan).Unix()
+	files := p.client.Files.ListAutoPaging(ctx, openai.FileListParams{
+		Purpose: openai.String(string(openai.FilePurposeUserData)),
+	})
+	deleted := 0
+	var errs []error
+	for files.Next() {
+		file := files.Current()
+		if !strings.HasPrefix(file.Filename, p.instancePrefix) || file.CreatedAt > cutoff {
+			continue
+		}
+		if _, err := p.client.Files.Delete(ctx, file.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete file %s: %w", file.ID, err))
+			continue
+		}
+		deleted++
+	}
+	if err := files.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to list files: %w", err))
+	}
+	return deleted, errors.Join(errs...)
 }
 
 // executeRequest is a helper function to run the chat completion and handle the response.
 func (p *OpenAIProvider) executeRequest(ctx context.Context, params responses.ResponseNewParams) (string, error) {
-	resp, err := p.client.Responses.New(ctx, params)
+	var resp *responses.Response
+	err := p.retry.do(ctx, "openai response", func(ctx context.Context) error {
+		var err error
+		resp, err = p.client.Responses.New(ctx, params)
+		if err != nil {
+			return classifyOpenAIError(err)
+		}
+		service.RecordUsage(ctx, model.UsageRecord{
+			Model:             p.modelName,
+			InputTokens:       resp.Usage.InputTokens,
+			CachedInputTokens: resp.Usage.InputTokensDetails.CachedTokens,
+			OutputTokens:      resp.Usage.OutputTokens,
+		})
+		return checkResponseStatus(resp)
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create responses with OpenAI: %w", err)
 	}
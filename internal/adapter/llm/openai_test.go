@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func TestDocumentInputRejectsFakePDF(t *testing.T) {
+	p := &OpenAIProvider{instancePrefix: uploadPrefix}
+	docx := []byte("PK\x03\x04\x14\x00\x06\x00word/document.xml")
+	for _, mimeType := range []string{"", pdfMimeType, "application/vnd.openxmlformats-officedocument.wordprocessingml.document"} {
+		file := model.File{Name: "Jean Dupont.docx", MimeType: mimeType, Content: docx}
+		_, fileID, err := p.documentInput(context.Background(), service.OperationParseResume, file)
+		if !errors.Is(err, service.ErrInvalidArgument) || fileID != "" {
+			t.Errorf("documentInput() with type %q = %q, %v, want ErrInvalidArgument", mimeType, fileID, err)
+		}
+	}
+}
+
+func TestUploadName(t *testing.T) {
+	p := &OpenAIProvider{instancePrefix: uploadPrefix + "prod-"}
+	name := p.uploadName([]byte("%PDF-1.7 Jean Dupont"))
+	if !strings.HasPrefix(name, "deckgen-prod-") || !strings.HasSuffix(name, ".pdf") || strings.Contains(name, "Dupont") {
+		t.Errorf("uploadName() = %q", name)
+	}
+	if again := p.uploadName([]byte("%PDF-1.7 Jean Dupont")); again != name {
+		t.Errorf("uploadName() is not stable: %q != %q", again, name)
+	}
+	if other := p.uploadName([]byte("%PDF-1.7 Marie Curie")); other == name {
+		t.Errorf("different documents share the name %q", name)
+	}
+}
@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/config"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// retryPolicy retries classified provider errors with exponential backoff and full jitter.
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxElapsed     time.Duration
+}
+
+func newRetryPolicy(cfg config.RetryConfig) retryPolicy {
+	return retryPolicy{
+		maxAttempts:    max(cfg.MaxAttempts, 1),
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		maxElapsed:     cfg.MaxElapsed,
+	}
+}
+
+// do runs fn until it succeeds, returns a non-retryable error, or the attempt or time budget is spent.
+// Errors returned by fn must already be classified as *service.LLMError to be retried.
+func (rp retryPolicy) do(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		attemptStart := time.Now()
+		err := fn(ctx)
+		observeAttempt(ctx, time.Since(attemptStart))
+		if err == nil {
+			return nil
+		}
+
+		var llmErr *service.LLMError
+		if !errors.As(err, &llmErr) {
+			return err
+		}
+		llmErr.Attempts = attempt
+		if !llmErr.Retryable() || attempt >= rp.maxAttempts {
+			return err
+		}
+
+		wait := rp.backoff(attempt, llmErr.RetryAfter)
+		if rp.maxElapsed > 0 && time.Since(start)+wait > rp.maxElapsed {
+			return err
+		}
+		log.Printf("%s: attempt %d/%d failed (%s), retrying in %s", operation, attempt, rp.maxAttempts, llmErr.Kind, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt. A Retry-After hint from the provider takes
+// precedence over the computed delay when it is longer.
+func (rp retryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	ceiling := rp.initialBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > rp.maxBackoff {
+		ceiling = rp.maxBackoff
+	}
+	var wait time.Duration
+	if ceiling > 0 {
+		wait = rand.N(ceiling) + 1 //nolint:gosec // jitter does not need a secure source
+	}
+	return max(wait, retryAfter)
+}
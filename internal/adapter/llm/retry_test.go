@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	rp := retryPolicy{initialBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		{"first attempt", 1, 0, time.Nanosecond, 100 * time.Millisecond},
+		{"doubles", 3, 0, time.Nanosecond, 400 * time.Millisecond},
+		{"capped", 10, 0, time.Nanosecond, time.Second},
+		{"overflow is capped", 80, 0, time.Nanosecond, time.Second},
+		{"longer retry after wins", 1, 5 * time.Second, 5 * time.Second, 5 * time.Second},
+		{"shorter retry after is a floor", 4, 50 * time.Millisecond, 50 * time.Millisecond, 800 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				if wait := rp.backoff(tt.attempt, tt.retryAfter); wait < tt.min || wait > tt.max {
+					t.Fatalf("backoff(%d, %s) = %s, want between %s and %s", tt.attempt, tt.retryAfter, wait, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	retryable := func() error {
+		return &service.LLMError{Kind: service.LLMErrorRetryable, Err: errors.New("server error")}
+	}
+	tests := []struct {
+		name         string
+		policy       retryPolicy
+		errs         []func() error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"success", retryPolicy{maxAttempts: 3}, nil, 1, false},
+		{"retried until success", retryPolicy{maxAttempts: 3}, []func() error{retryable, retryable}, 3, false},
+		{"attempts exhausted", retryPolicy{maxAttempts: 2}, []func() error{retryable, retryable, retryable}, 2, true},
+		{"rate limited is retried", retryPolicy{maxAttempts: 2}, []func() error{func() error {
+			return &service.LLMError{Kind: service.LLMErrorRateLimited, RetryAfter: time.Millisecond, Err: errors.New("slow down")}
+		}}, 2, false},
+		{"invalid request is not retried", retryPolicy{maxAttempts: 3}, []func() error{func() error {
+			return &service.LLMError{Kind: service.LLMErrorInvalidRequest, Err: errors.New("bad request")}
+		}}, 1, true},
+		{"unclassified error is not retried", retryPolicy{maxAttempts: 3}, []func() error{func() error {
+			return errors.New("unclassified")
+		}}, 1, true},
+		{"retry after beyond max elapsed", retryPolicy{maxAttempts: 3, maxElapsed: time.Second}, []func() error{func() error {
+			return &service.LLMError{Kind: service.LLMErrorRateLimited, RetryAfter: time.Minute, Err: errors.New("slow down")}
+		}}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := tt.policy.do(context.Background(), "test", func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]()
+				}
+				return nil
+			})
+			if attempts != tt.wantAttempts || (err != nil) != tt.wantErr {
+				t.Errorf("got %d attempts and error %v, want %d attempts and error %t", attempts, err, tt.wantAttempts, tt.wantErr)
+			}
+			var llmErr *service.LLMError
+			if errors.As(err, &llmErr) && llmErr.Attempts != attempts {
+				t.Errorf("error records %d attempts, want %d", llmErr.Attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rp := retryPolicy{maxAttempts: 3, initialBackoff: time.Minute, maxBackoff: time.Minute}
+	err := rp.do(ctx, "test", func(ctx context.Context) error {
+		cancel()
+		return &service.LLMError{Kind: service.LLMErrorRetryable, Err: errors.New("server error")}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"none", http.Header{}, 0},
+		{"milliseconds", http.Header{"Retry-After-Ms": {"250"}}, 250 * time.Millisecond},
+		{"seconds", http.Header{"Retry-After": {"2"}}, 2 * time.Second},
+		{"milliseconds win", http.Header{"Retry-After-Ms": {"100"}, "Retry-After": {"2"}}, 100 * time.Millisecond},
+		{"past date", http.Header{"Retry-After": {"Mon, 02 Jan 2006 15:04:05 GMT"}}, 0},
+		{"garbage", http.Header{"Retry-After": {"soon"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header); got != tt.want {
+				t.Errorf("parseRetryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// decodeOutput decodes a model output into T and returns every problem found by validating it
+// against schema and the semantic rules of check.
+func decodeOutput[T any](raw string, schema map[string]interface{}, check func(T) []string) (T, []string) {
+	var result T
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return result, []string{fmt.Sprintf("the output is not valid JSON: %v", err)}
+	}
+	if problems := validateAgainstSchema(schema, value, "$"); len(problems) > 0 {
+		return result, problems
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return result, []string{fmt.Sprintf("the output does not match the expected types: %v", err)}
+	}
+	if check == nil {
+		return result, nil
+	}
+	return result, check(result)
+}
+
+// validateAgainstSchema checks a decoded JSON value against the subset of JSON schema produced by
+// GenerateSchema, and returns one problem per violation prefixed by the path of the value.
+func validateAgainstSchema(schema map[string]interface{}, value any, path string) []string {
+	if schemaType, ok := schema["type"].(string); ok && !matchesType(schemaType, value) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, schemaType, jsonType(value))}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, enum)}
+	}
+	if format, ok := schema["format"].(string); ok && format == "date-time" {
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return []string{fmt.Sprintf("%s: %q is not an RFC 3339 date-time", path, s)}
+			}
+		}
+	}
+
+	var problems []string
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			propertySchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					problems = append(problems, fmt.Sprintf("%s: unexpected property %q", path, name))
+				}
+				continue
+			}
+			problems = append(problems, validateAgainstSchema(propertySchema, v[name], path+"."+name)...)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				problems = append(problems, validateAgainstSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func matchesType(schemaType string, value any) bool {
+	switch schemaType {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseInt(n.String(), 10, 64)
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonType(value) == schemaType
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func inEnum(enum []interface{}, value any) bool {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range enum {
+		allowedRaw, err := json.Marshal(allowed)
+		if err == nil && bytes.Equal(raw, allowedRaw) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAdaptation applies the rules of a parsed resume to the adapted one, and requires every
+// rationale to name the section it explains.
+func checkAdaptation(adaptation model.ResumeAdaptation) []string {
+	problems := model.CheckResume(adaptation.Resume)
+	for i := range problems {
+		problems[i] = strings.Replace(problems[i], "$.", "$.resume.", 1)
+	}
+	for i, rationale := range adaptation.Rationales {
+		if strings.TrimSpace(rationale.Section) == "" {
+			problems = append(problems, fmt.Sprintf("$.rationales[%d].section: the section must not be empty", i))
+		}
+	}
+	return problems
+}
@@ -9,14 +9,142 @@ import (
 
 type Config struct {
 	Server       ServerConfig                 `koanf:"server" yaml:"server"`
+	Auth         AuthConfig                   `koanf:"auth" yaml:"auth"`
 	Log          LogConfig                    `koanf:"log" yaml:"log"`
 	LLMProviders map[string]LLMProviderConfig `koanf:"llm_providers" yaml:"llm_providers"`
+	LLMRouting   map[string]LLMRouteConfig    `koanf:"llm_routing" yaml:"llm_routing"`
+	LLMPricing   map[string]ModelPriceConfig  `koanf:"llm_pricing" yaml:"llm_pricing"`
+	LLMBudgets   []BudgetConfig               `koanf:"llm_budgets" yaml:"llm_budgets"`
+	Cache        CacheConfig                  `koanf:"extraction_cache" yaml:"extraction_cache"`
+	Prompts      PromptsConfig                `koanf:"prompts" yaml:"prompts"`
+	Extraction   ExtractionConfig             `koanf:"extraction" yaml:"extraction"`
+	Adaptation   AdaptationConfig             `koanf:"adaptation" yaml:"adaptation"`
+	Taxonomy     TaxonomyConfig               `koanf:"taxonomy" yaml:"taxonomy"`
+	Currency     CurrencyConfig               `koanf:"currency" yaml:"currency"`
 	Logger       zerolog.Logger               `koanf:"-" yaml:"-"`
 }
+
+// LLMRouteConfig is the ordered provider chain of an operation: the first provider is the
+// default one and the following ones are tried in order when it fails or times out.
+type LLMRouteConfig struct {
+	Providers []string      `koanf:"providers" yaml:"providers"`
+	Timeout   time.Duration `koanf:"timeout" yaml:"timeout"`
+}
 type LLMProviderConfig struct {
-	Enabled bool   `koanf:"enabled" yaml:"enabled"`
-	APIKey  string `koanf:"api_key" yaml:"api_key"`
-	Model   string `koanf:"model" yaml:"model"`
+	Enabled bool          `koanf:"enabled" yaml:"enabled"`
+	APIKey  string        `koanf:"api_key" yaml:"api_key"`
+	Model   string        `koanf:"model" yaml:"model"`
+	Retry   RetryConfig   `koanf:"retry" yaml:"retry"`
+	Breaker BreakerConfig `koanf:"circuit_breaker" yaml:"circuit_breaker"`
+	// MaxRepairs is how many times an invalid output is sent back to the model to be fixed.
+	MaxRepairs  int               `koanf:"max_repairs" yaml:"max_repairs"`
+	FileSweeper FileSweeperConfig `koanf:"file_sweeper" yaml:"file_sweeper"`
+	// Personal data is replaced with placeholders in what is sent to every provider, unless
+	// DisablePseudonymization is set, which is meant for models running locally.
+	DisablePseudonymization bool `koanf:"disable_pseudonymization" yaml:"disable_pseudonymization"`
+}
+
+// FileSweeperConfig deletes, every interval, the uploaded files older than max_age that were not
+// deleted after use. A zero interval disables the sweeper.
+type FileSweeperConfig struct {
+	Interval time.Duration `koanf:"interval" yaml:"interval"`
+	MaxAge   time.Duration `koanf:"max_age" yaml:"max_age"`
+	// Instance is part of the names of the files this deployment uploads, and the sweeper only
+	// deletes files with its own instance. Deployments sharing an API key need distinct instances.
+	Instance string `koanf:"instance" yaml:"instance"`
+}
+
+// BreakerConfig controls the circuit breaker of a provider. A zero failure_threshold disables it.
+type BreakerConfig struct {
+	FailureThreshold int           `koanf:"failure_threshold" yaml:"failure_threshold"`
+	SlowCallDuration time.Duration `koanf:"slow_call_duration" yaml:"slow_call_duration"`
+	OpenDuration     time.Duration `koanf:"open_duration" yaml:"open_duration"`
+}
+
+// RetryConfig controls how provider calls are retried. A zero max_attempts disables retries.
+type RetryConfig struct {
+	MaxAttempts    int           `koanf:"max_attempts" yaml:"max_attempts"`
+	InitialBackoff time.Duration `koanf:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `koanf:"max_backoff" yaml:"max_backoff"`
+	MaxElapsed     time.Duration `koanf:"max_elapsed" yaml:"max_elapsed"`
+}
+
+// ModelPriceConfig is the price of a model in USD per million tokens.
+type ModelPriceConfig struct {
+	InputPerMillion       float64 `koanf:"input_per_million" yaml:"input_per_million"`
+	CachedInputPerMillion float64 `koanf:"cached_input_per_million" yaml:"cached_input_per_million"`
+	OutputPerMillion      float64 `koanf:"output_per_million" yaml:"output_per_million"`
+}
+
+// BudgetConfig is a monthly LLM spending limit in USD for a tenant, user or provider.
+// A key of "*" applies the limits to each tenant, user or provider separately.
+type BudgetConfig struct {
+	Name         string  `koanf:"name" yaml:"name"`
+	Scope        string  `koanf:"scope" yaml:"scope"`
+	Key          string  `koanf:"key" yaml:"key"`
+	SoftLimitUSD float64 `koanf:"soft_limit_usd" yaml:"soft_limit_usd"`
+	HardLimitUSD float64 `koanf:"hard_limit_usd" yaml:"hard_limit_usd"`
+}
+
+// CacheConfig selects where parse results are cached: "memory", "disk" (in dir) or "none".
+// A zero ttl keeps entries forever.
+type CacheConfig struct {
+	Backend string        `koanf:"backend" yaml:"backend"`
+	Dir     string        `koanf:"dir" yaml:"dir"`
+	TTL     time.Duration `koanf:"ttl" yaml:"ttl"`
+}
+
+// PromptsConfig points to an optional directory of <name>.<locale>.tmpl files overriding the
+// embedded prompts.
+type PromptsConfig struct {
+	Dir string `koanf:"dir" yaml:"dir"`
+}
+
+// TaxonomyConfig points to an optional YAML file of skill terms overriding the embedded taxonomy,
+// where the edits made through the API are saved. Edits are kept in memory only without it.
+type TaxonomyConfig struct {
+	File string `koanf:"file" yaml:"file"`
+}
+
+// CurrencyConfig converts rates to daily rates in the base currency, so that they can be compared.
+// Rates maps ISO 4217 codes to the value of one unit of the currency in the base currency, rates
+// in other currencies not being converted. It is empty by default, exchange rates changing too
+// often to be shipped, and must be kept up to date by operators. Monthly and hourly rates are
+// converted with days_per_month and hours_per_day, zero values defaulting to 20 and 8.
+type CurrencyConfig struct {
+	Base         string             `koanf:"base" yaml:"base"`
+	Rates        map[string]float64 `koanf:"rates" yaml:"rates"`
+	HoursPerDay  float64            `koanf:"hours_per_day" yaml:"hours_per_day"`
+	DaysPerMonth float64            `koanf:"days_per_month" yaml:"days_per_month"`
+}
+
+// ExtractionConfig tunes the local text extraction of uploaded documents.
+type ExtractionConfig struct {
+	OCR      OCRConfig      `koanf:"ocr" yaml:"ocr"`
+	Chunking ChunkingConfig `koanf:"chunking" yaml:"chunking"`
+}
+
+// ChunkingConfig splits documents longer than max_chars into chunks extracted separately, at
+// most concurrency at a time. A zero max_chars disables chunking.
+type ChunkingConfig struct {
+	MaxChars    int `koanf:"max_chars" yaml:"max_chars"`
+	Concurrency int `koanf:"concurrency" yaml:"concurrency"`
+}
+
+// OCRConfig tunes the OCR of images and scanned PDFs, which requires a build with the ocr tag.
+// Pages whose mean word confidence (0-100) is below min_confidence are reported as low quality,
+// a zero min_confidence disabling the warning.
+type OCRConfig struct {
+	Languages     []string `koanf:"languages" yaml:"languages"`
+	MinConfidence float64  `koanf:"min_confidence" yaml:"min_confidence"`
+	DPI           int      `koanf:"dpi" yaml:"dpi"`
+}
+
+// AdaptationConfig tunes resume adaptation. Grounding is what happens to the claims of an adapted
+// resume that its source resumes do not support: "flag" reports them for review, "strip" also
+// removes them and "off" skips the check.
+type AdaptationConfig struct {
+	Grounding string `koanf:"grounding" yaml:"grounding"`
 }
 
 type ServerConfig struct {
@@ -28,6 +156,21 @@ type ServerConfig struct {
 	MaxHeaderBytes        int           `koanf:"max_header_bytes" yaml:"max_header_bytes"`
 }
 
+// AuthConfig lists the API keys clients send as "Authorization: Bearer <key>". Each key identifies
+// the tenant and user to whom usage and budgets are attributed, and admin keys may also read usage
+// and provider health, and change budgets and the taxonomy. Without keys, every request is
+// anonymous and admin endpoints are refused.
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `koanf:"api_keys" yaml:"api_keys"`
+}
+
+type APIKeyConfig struct {
+	Key      string `koanf:"key" yaml:"key"`
+	TenantID string `koanf:"tenant_id" yaml:"tenant_id"`
+	UserID   string `koanf:"user_id" yaml:"user_id"`
+	Admin    bool   `koanf:"admin" yaml:"admin"`
+}
+
 type LogConfig struct {
 	Level  string `koanf:"level" yaml:"level"`
 	Pretty bool   `koanf:"pretty" yaml:"pretty"`
@@ -49,6 +192,68 @@ var Default = Config{
 	LLMProviders: map[string]LLMProviderConfig{
 		"openai": {
 			Model: "gpt-5-mini",
+			Retry: RetryConfig{
+				MaxAttempts:    4,
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     20 * time.Second,
+				MaxElapsed:     60 * time.Second,
+			},
+			Breaker: BreakerConfig{
+				FailureThreshold: 5,
+				SlowCallDuration: 30 * time.Second,
+				OpenDuration:     30 * time.Second,
+			},
+			MaxRepairs: 2,
+			FileSweeper: FileSweeperConfig{
+				Interval: 30 * time.Minute,
+				MaxAge:   time.Hour,
+				Instance: "default",
+			},
+		},
+	},
+	LLMRouting: map[string]LLMRouteConfig{
+		"parse_resume": {Providers: []string{"openai"}, Timeout: 45 * time.Second},
+		"parse_job_ad": {Providers: []string{"openai"}, Timeout: 45 * time.Second},
+		"adapt_resume": {Providers: []string{"openai"}, Timeout: 90 * time.Second},
+	},
+	LLMPricing: map[string]ModelPriceConfig{
+		"gpt-5-mini": {
+			InputPerMillion:       0.25,
+			CachedInputPerMillion: 0.025,
+			OutputPerMillion:      2,
+		},
+	},
+	Cache: CacheConfig{
+		Backend: "memory",
+		TTL:     30 * 24 * time.Hour,
+	},
+	Extraction: ExtractionConfig{
+		OCR: OCRConfig{
+			Languages:     []string{"fra", "eng"},
+			MinConfidence: 60,
+			DPI:           300,
+		},
+		Chunking: ChunkingConfig{
+			MaxChars:    40000,
+			Concurrency: 4,
+		},
+	},
+	Adaptation: AdaptationConfig{
+		Grounding: "flag",
+	},
+	Currency: CurrencyConfig{
+		Base:         "EUR",
+		Rates:        map[string]float64{},
+		HoursPerDay:  8,
+		DaysPerMonth: 20,
+	},
+	LLMBudgets: []BudgetConfig{
+		{
+			Name:         "per-tenant",
+			Scope:        "tenant",
+			Key:          "*",
+			SoftLimitUSD: 80,
+			HardLimitUSD: 100,
 		},
 	},
 }
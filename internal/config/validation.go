@@ -3,17 +3,80 @@ package config
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
 )
 
+var routableOperations = []string{"parse_resume", "parse_job_ad", "adapt_resume"}
+
 func (c *Config) validate() error {
 	if err := c.Server.validate(); err != nil {
 		return err
 	}
+	if err := c.Auth.validate(); err != nil {
+		return fmt.Errorf("auth config error: %w", err)
+	}
 	for name, provider := range c.LLMProviders {
 		if err := provider.validate(); err != nil {
 			return fmt.Errorf("provider '%s' config error: %w", name, err)
 		}
 	}
+	for modelName, price := range c.LLMPricing {
+		if err := price.validate(); err != nil {
+			return fmt.Errorf("llm pricing '%s' config error: %w", modelName, err)
+		}
+	}
+	if err := c.Cache.validate(); err != nil {
+		return fmt.Errorf("extraction cache config error: %w", err)
+	}
+	if err := c.Extraction.OCR.validate(); err != nil {
+		return fmt.Errorf("extraction ocr config error: %w", err)
+	}
+	if err := c.Extraction.Chunking.validate(); err != nil {
+		return fmt.Errorf("extraction chunking config error: %w", err)
+	}
+	if err := c.Adaptation.validate(); err != nil {
+		return fmt.Errorf("adaptation config error: %w", err)
+	}
+	if err := c.Currency.validate(); err != nil {
+		return fmt.Errorf("currency config error: %w", err)
+	}
+	budgetNames := make(map[string]bool, len(c.LLMBudgets))
+	for _, budget := range c.LLMBudgets {
+		if err := budget.validate(); err != nil {
+			return fmt.Errorf("llm budget '%s' config error: %w", budget.Name, err)
+		}
+		if budgetNames[budget.Name] {
+			return fmt.Errorf("llm budget '%s' is defined more than once", budget.Name)
+		}
+		budgetNames[budget.Name] = true
+	}
+	for operation, route := range c.LLMRouting {
+		if err := c.validateRoute(operation, route); err != nil {
+			return fmt.Errorf("llm routing '%s' config error: %w", operation, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateRoute(operation string, route LLMRouteConfig) error {
+	if !slices.Contains(routableOperations, operation) {
+		return fmt.Errorf("unknown operation, expected one of %s", strings.Join(routableOperations, ", "))
+	}
+	if len(route.Providers) == 0 {
+		return errors.New("at least one provider is required")
+	}
+	for _, name := range route.Providers {
+		if _, ok := c.LLMProviders[name]; !ok {
+			return fmt.Errorf("provider '%s' is not configured", name)
+		}
+	}
+	if route.Timeout < 0 {
+		return errors.New("timeout must be positive")
+	}
 	return nil
 }
 
@@ -39,6 +102,26 @@ func (sc ServerConfig) validate() error {
 	return nil
 }
 
+// minAPIKeyLength keeps API keys long enough not to be guessed.
+const minAPIKeyLength = 24
+
+func (ac AuthConfig) validate() error {
+	keys := make(map[string]bool, len(ac.APIKeys))
+	for i, key := range ac.APIKeys {
+		if len(key.Key) < minAPIKeyLength {
+			return fmt.Errorf("api key %d must be at least %d characters long", i, minAPIKeyLength)
+		}
+		if key.TenantID == "" {
+			return fmt.Errorf("api key %d has no tenant_id", i)
+		}
+		if keys[key.Key] {
+			return fmt.Errorf("api key %d is defined more than once", i)
+		}
+		keys[key.Key] = true
+	}
+	return nil
+}
+
 func (lpc LLMProviderConfig) validate() error {
 	if !lpc.Enabled {
 		return nil
@@ -49,5 +132,171 @@ func (lpc LLMProviderConfig) validate() error {
 	if lpc.Model == "" {
 		return errors.New("model name is required")
 	}
+	if err := lpc.Retry.validate(); err != nil {
+		return fmt.Errorf("retry config error: %w", err)
+	}
+	if err := lpc.Breaker.validate(); err != nil {
+		return fmt.Errorf("circuit_breaker config error: %w", err)
+	}
+	if lpc.MaxRepairs < 0 {
+		return errors.New("max_repairs must be positive")
+	}
+	if err := lpc.FileSweeper.validate(); err != nil {
+		return fmt.Errorf("file_sweeper config error: %w", err)
+	}
+	return nil
+}
+
+func (fsc FileSweeperConfig) validate() error {
+	if fsc.Interval < 0 {
+		return errors.New("interval must be positive")
+	}
+	if fsc.Interval > 0 && fsc.MaxAge <= 0 {
+		return errors.New("max_age is required when the sweeper is enabled")
+	}
+	if fsc.Interval > 0 && fsc.Instance == "" {
+		return errors.New("instance is required when the sweeper is enabled")
+	}
+	if fsc.Instance != "" && !instanceName.MatchString(fsc.Instance) {
+		return fmt.Errorf("instance '%s' must only contain lowercase letters, digits and underscores", fsc.Instance)
+	}
+	return nil
+}
+
+// instanceName excludes hyphens, so that the upload prefix of an instance is never a prefix of
+// the one of another instance.
+var instanceName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func (bc BreakerConfig) validate() error {
+	if bc.FailureThreshold < 0 {
+		return errors.New("failure_threshold must be positive")
+	}
+	if bc.FailureThreshold == 0 {
+		return nil
+	}
+	if bc.OpenDuration <= 0 {
+		return errors.New("open_duration must be strictly positive when the breaker is enabled")
+	}
+	if bc.SlowCallDuration < 0 {
+		return errors.New("slow_call_duration must be positive")
+	}
+	return nil
+}
+
+func (rc RetryConfig) validate() error {
+	if rc.MaxAttempts < 0 {
+		return errors.New("max_attempts must be positive")
+	}
+	if rc.MaxAttempts <= 1 {
+		return nil
+	}
+	if rc.InitialBackoff <= 0 {
+		return errors.New("initial_backoff must be strictly positive when retries are enabled")
+	}
+	if rc.MaxBackoff < rc.InitialBackoff {
+		return errors.New("max_backoff must be greater than or equal to initial_backoff")
+	}
+	if rc.MaxElapsed < 0 {
+		return errors.New("max_elapsed must be positive")
+	}
+	return nil
+}
+
+func (mpc ModelPriceConfig) validate() error {
+	if mpc.InputPerMillion < 0 || mpc.CachedInputPerMillion < 0 || mpc.OutputPerMillion < 0 {
+		return errors.New("prices must be positive")
+	}
+	return nil
+}
+
+func (bc BudgetConfig) validate() error {
+	if bc.Name == "" {
+		return errors.New("name is required")
+	}
+	if bc.Scope != "tenant" && bc.Scope != "user" && bc.Scope != "provider" {
+		return errors.New("scope must be one of tenant, user, provider")
+	}
+	if bc.Key == "" {
+		return errors.New("key is required, use '*' to apply the budget to every key")
+	}
+	if bc.SoftLimitUSD < 0 || bc.HardLimitUSD < 0 {
+		return errors.New("limits must be positive")
+	}
+	if bc.SoftLimitUSD > 0 && bc.HardLimitUSD > 0 && bc.SoftLimitUSD > bc.HardLimitUSD {
+		return errors.New("soft_limit_usd must not be greater than hard_limit_usd")
+	}
+	return nil
+}
+
+func (cc CacheConfig) validate() error {
+	switch cc.Backend {
+	case "", "none", "memory":
+	case "disk":
+		if cc.Dir == "" {
+			return errors.New("dir is required for the disk backend")
+		}
+	default:
+		return fmt.Errorf("unknown backend '%s', expected memory, disk or none", cc.Backend)
+	}
+	if cc.TTL < 0 {
+		return errors.New("ttl must be positive")
+	}
 	return nil
 }
+
+func (oc OCRConfig) validate() error {
+	if oc.MinConfidence < 0 || oc.MinConfidence > 100 {
+		return errors.New("min_confidence must be between 0 and 100")
+	}
+	if oc.DPI < 0 {
+		return errors.New("dpi must be positive")
+	}
+	return nil
+}
+
+func (cc ChunkingConfig) validate() error {
+	if cc.MaxChars < 0 {
+		return errors.New("max_chars must be positive")
+	}
+	if cc.MaxChars > 0 && cc.MaxChars < 1000 {
+		return errors.New("max_chars must be at least 1000")
+	}
+	if cc.Concurrency < 0 {
+		return errors.New("concurrency must be positive")
+	}
+	return nil
+}
+
+func (ac AdaptationConfig) validate() error {
+	switch ac.Grounding {
+	case "", "flag", "strip", "off":
+		return nil
+	default:
+		return fmt.Errorf("unknown grounding '%s', expected flag, strip or off", ac.Grounding)
+	}
+}
+
+func (cc CurrencyConfig) validate() error {
+	if !isCurrencyCode(cc.Base) {
+		return fmt.Errorf("base '%s' is not an ISO 4217 code", cc.Base)
+	}
+	for code, rate := range cc.Rates {
+		if !isCurrencyCode(code) {
+			return fmt.Errorf("rate '%s' is not an ISO 4217 code", code)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("rate '%s' must be positive", code)
+		}
+	}
+	if cc.HoursPerDay < 0 || cc.HoursPerDay > 24 {
+		return errors.New("hours_per_day must be between 0 and 24")
+	}
+	if cc.DaysPerMonth < 0 || cc.DaysPerMonth > 31 {
+		return errors.New("days_per_month must be between 0 and 31")
+	}
+	return nil
+}
+
+func isCurrencyCode(code string) bool {
+	return model.IsCurrencyCode(strings.ToUpper(code))
+}
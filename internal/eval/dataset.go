@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// Kinds of documents, named after the dataset directory holding them.
+const (
+	KindResume = "resumes"
+	KindJobAd  = "job-ads"
+)
+
+// Case is a labelled document: a file and the entity it is expected to be parsed into.
+type Case struct {
+	Kind           string
+	Name           string
+	File           model.File
+	ExpectedResume model.CandidateResume
+	ExpectedJobAd  model.JobAd
+}
+
+// LoadDataset reads the labelled documents of dir. Resumes are read from dir/resumes and job
+// ads from dir/job-ads, each document <name>.<ext> being labelled by the expected JSON entity in
+// <name>.json next to it.
+func LoadDataset(dir string) ([]Case, error) {
+	var cases []Case
+	for _, kind := range []string{KindResume, KindJobAd} {
+		kindCases, err := loadKind(filepath.Join(dir, kind), kind)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, kindCases...)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no labelled document found in %s/%s or %s/%s", dir, KindResume, dir, KindJobAd)
+	}
+	return cases, nil
+}
+
+func loadKind(dir, kind string) ([]Case, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	documents := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || ext == ".json" || strings.HasPrefix(name, ".") {
+			continue
+		}
+		base := strings.TrimSuffix(name, ext)
+		if other, ok := documents[base]; ok {
+			return nil, fmt.Errorf("%s and %s in %s share the label %s.json", other, name, dir, base)
+		}
+		documents[base] = name
+	}
+
+	bases := make([]string, 0, len(documents))
+	for base := range documents {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+
+	cases := make([]Case, 0, len(bases))
+	for _, base := range bases {
+		c, err := loadCase(dir, kind, base, documents[base])
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func loadCase(dir, kind, base, name string) (Case, error) {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return Case{}, fmt.Errorf("failed to read document: %w", err)
+	}
+	labelPath := filepath.Join(dir, base+".json")
+	label, err := os.ReadFile(labelPath)
+	if err != nil {
+		return Case{}, fmt.Errorf("document %s has no label: %w", name, err)
+	}
+
+	c := Case{
+		Kind: kind,
+		Name: kind + "/" + name,
+		File: model.File{
+			Name:      name,
+			Extension: strings.ToLower(strings.TrimPrefix(filepath.Ext(name), ".")),
+			Content:   content,
+		},
+	}
+	switch kind {
+	case KindResume:
+		err = json.Unmarshal(label, &c.ExpectedResume)
+	default:
+		err = json.Unmarshal(label, &c.ExpectedJobAd)
+	}
+	if err != nil {
+		return Case{}, fmt.Errorf("invalid label %s: %w", labelPath, err)
+	}
+	return c, nil
+}
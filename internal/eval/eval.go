@@ -0,0 +1,155 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// Parser parses documents with a given provider, as the SynthesizerService does.
+type Parser interface {
+	ParseResume(ctx context.Context, file model.File, opts service.ParseOptions) (model.CandidateResume, error)
+	ParseJobAd(ctx context.Context, file model.File, opts service.ParseOptions) (model.JobAd, error)
+}
+
+// Run is the evaluation of one provider with one set of prompts over a dataset.
+type Run struct {
+	Label          string        `json:"label"`
+	Provider       string        `json:"provider"`
+	Prompts        string        `json:"prompts"`
+	PromptVersions []string      `json:"prompt_versions"`
+	Documents      int           `json:"documents"`
+	ExactDocuments int           `json:"exact_documents"`
+	Duration       time.Duration `json:"duration"`
+	Overall        FieldScore    `json:"overall"`
+	Fields         []FieldScore  `json:"fields"`
+	Failures       []Failure     `json:"failures,omitempty"`
+}
+
+// FieldScore is the precision, recall and exact-match rate of a field over the documents of a
+// run. Overall scores sum the counts of every field.
+type FieldScore struct {
+	Field          string  `json:"field"`
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	ExactMatches   int     `json:"exact_matches"`
+	Documents      int     `json:"documents"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+	ExactMatch     float64 `json:"exact_match"`
+}
+
+// Failure is a document the provider could not parse. It counts as extracting nothing.
+type Failure struct {
+	Case  string `json:"case"`
+	Error string `json:"error"`
+}
+
+// Evaluate parses every case with provider, always bypassing the extraction cache, and scores
+// the results against the labels.
+func Evaluate(ctx context.Context, parser Parser, provider, prompts string, cases []Case) Run {
+	run := Run{
+		Label:     fmt.Sprintf("%s / %s", provider, prompts),
+		Provider:  provider,
+		Prompts:   prompts,
+		Documents: len(cases),
+	}
+	opts := service.ParseOptions{Provider: service.LLMProviderName(provider), ForceRefresh: true}
+	totals := make(map[string]*FieldScore)
+	start := time.Now()
+	for _, c := range cases {
+		var scores fieldCounts
+		var prefix, promptVersion string
+		var err error
+		switch c.Kind {
+		case KindResume:
+			var resume model.CandidateResume
+			resume, err = parser.ParseResume(ctx, c.File, opts)
+			prefix, promptVersion = "resume.", resume.PromptVersion
+			scores = scoreResume(c.ExpectedResume, resume)
+		default:
+			var jobAd model.JobAd
+			jobAd, err = parser.ParseJobAd(ctx, c.File, opts)
+			prefix, promptVersion = "job_ad.", jobAd.PromptVersion
+			scores = scoreJobAd(c.ExpectedJobAd, jobAd)
+		}
+		if err != nil {
+			run.Failures = append(run.Failures, Failure{Case: c.Name, Error: err.Error()})
+		}
+		if promptVersion != "" && !slices.Contains(run.PromptVersions, promptVersion) {
+			run.PromptVersions = append(run.PromptVersions, promptVersion)
+		}
+
+		exact := true
+		for field, count := range scores {
+			name := prefix + field
+			if totals[name] == nil {
+				totals[name] = &FieldScore{Field: name}
+			}
+			totals[name].add(count)
+			exact = exact && count.exact
+		}
+		if exact {
+			run.ExactDocuments++
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	run.Duration = time.Since(start).Round(time.Millisecond)
+	sort.Strings(run.PromptVersions)
+
+	run.Overall = FieldScore{Field: "overall"}
+	for _, total := range totals {
+		total.finish()
+		run.Fields = append(run.Fields, *total)
+		run.Overall.TruePositives += total.TruePositives
+		run.Overall.FalsePositives += total.FalsePositives
+		run.Overall.FalseNegatives += total.FalseNegatives
+		run.Overall.ExactMatches += total.ExactMatches
+		run.Overall.Documents += total.Documents
+	}
+	run.Overall.finish()
+	sort.Slice(run.Fields, func(i, j int) bool { return run.Fields[i].Field < run.Fields[j].Field })
+	return run
+}
+
+func (f *FieldScore) add(c counts) {
+	f.TruePositives += c.truePositives
+	f.FalsePositives += c.falsePositives
+	f.FalseNegatives += c.falseNegatives
+	f.Documents++
+	if c.exact {
+		f.ExactMatches++
+	}
+}
+
+// finish computes the rates from the counts. Precision and recall are 1 when there was nothing
+// to extract and nothing was extracted.
+func (f *FieldScore) finish() {
+	f.Precision = ratio(f.TruePositives, f.TruePositives+f.FalsePositives)
+	f.Recall = ratio(f.TruePositives, f.TruePositives+f.FalseNegatives)
+	if f.Precision+f.Recall > 0 {
+		f.F1 = round(2 * f.Precision * f.Recall / (f.Precision + f.Recall))
+	}
+	f.ExactMatch = ratio(f.ExactMatches, f.Documents)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 1
+	}
+	return round(float64(n) / float64(d))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,124 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report compares the runs of an evaluation, the first run being the baseline of the others.
+type Report struct {
+	Dataset   string    `json:"dataset"`
+	CreatedAt time.Time `json:"created_at"`
+	Resumes   int       `json:"resumes"`
+	JobAds    int       `json:"job_ads"`
+	Runs      []Run     `json:"runs"`
+}
+
+func NewReport(dataset string, cases []Case, runs []Run) Report {
+	report := Report{Dataset: dataset, CreatedAt: time.Now(), Runs: runs}
+	for _, c := range cases {
+		if c.Kind == KindResume {
+			report.Resumes++
+		} else {
+			report.JobAds++
+		}
+	}
+	return report
+}
+
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteMarkdown writes a summary of every run, the F1 score of every field in each run with its
+// change against the baseline, and the documents that could not be parsed.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Extraction evaluation\n\n")
+	fmt.Fprintf(&b, "Dataset `%s`: %d resumes, %d job ads, evaluated on %s.\n\n",
+		r.Dataset, r.Resumes, r.JobAds, r.CreatedAt.Format(time.DateTime))
+
+	fmt.Fprintf(&b, "## Runs\n\n")
+	fmt.Fprintf(&b, "| Run | Prompt versions | Failures | Precision | Recall | F1 | Exact documents | Duration |\n")
+	fmt.Fprintf(&b, "|---|---|---|---|---|---|---|---|\n")
+	for _, run := range r.Runs {
+		fmt.Fprintf(&b, "| %s | %s | %d | %.3f | %.3f | %.3f | %d/%d | %s |\n",
+			run.Label, strings.Join(run.PromptVersions, ", "), len(run.Failures),
+			run.Overall.Precision, run.Overall.Recall, run.Overall.F1,
+			run.ExactDocuments, run.Documents, run.Duration)
+	}
+
+	if len(r.Runs) > 0 {
+		fmt.Fprintf(&b, "\n## F1 by field\n\n")
+		if len(r.Runs) > 1 {
+			fmt.Fprintf(&b, "Changes are against the baseline `%s`.\n\n", r.Runs[0].Label)
+		}
+		fmt.Fprintf(&b, "| Field |")
+		for _, run := range r.Runs {
+			fmt.Fprintf(&b, " %s |", run.Label)
+		}
+		fmt.Fprintf(&b, "\n|---|%s\n", strings.Repeat("---|", len(r.Runs)))
+		for _, field := range r.fieldNames() {
+			fmt.Fprintf(&b, "| %s |", field)
+			baseline, hasBaseline := r.Runs[0].field(field)
+			for i, run := range r.Runs {
+				score, ok := run.field(field)
+				switch {
+				case !ok:
+					fmt.Fprintf(&b, " - |")
+				case i == 0 || !hasBaseline:
+					fmt.Fprintf(&b, " %.3f |", score.F1)
+				default:
+					fmt.Fprintf(&b, " %.3f (%+.3f) |", score.F1, score.F1-baseline.F1)
+				}
+			}
+			fmt.Fprintf(&b, "\n")
+		}
+	}
+
+	for _, run := range r.Runs {
+		fmt.Fprintf(&b, "\n## %s\n\n", run.Label)
+		fmt.Fprintf(&b, "| Field | Precision | Recall | F1 | Exact match |\n|---|---|---|---|---|\n")
+		for _, score := range run.Fields {
+			fmt.Fprintf(&b, "| %s | %.3f | %.3f | %.3f | %d/%d |\n",
+				score.Field, score.Precision, score.Recall, score.F1, score.ExactMatches, score.Documents)
+		}
+		if len(run.Failures) > 0 {
+			fmt.Fprintf(&b, "\nFailures:\n\n")
+			for _, failure := range run.Failures {
+				fmt.Fprintf(&b, "- `%s`: %s\n", failure.Case, failure.Error)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fieldNames returns the fields scored by any run, in the order of the first run that has them.
+func (r Report) fieldNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, run := range r.Runs {
+		for _, score := range run.Fields {
+			if !seen[score.Field] {
+				seen[score.Field] = true
+				names = append(names, score.Field)
+			}
+		}
+	}
+	return names
+}
+
+func (run Run) field(name string) (FieldScore, bool) {
+	for _, score := range run.Fields {
+		if score.Field == name {
+			return score, true
+		}
+	}
+	return FieldScore{}, false
+}
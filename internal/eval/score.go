@@ -0,0 +1,257 @@
+package eval
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// minTextSimilarity is the share of common words above which two free texts are considered the
+// same, as models rarely reproduce descriptions word for word.
+const minTextSimilarity = 0.5
+
+var toolSeparators = regexp.MustCompile(`\s*[,;/|]\s*`)
+
+// counts are the matches of a field in one document or, summed, in a dataset. A field whose
+// expected and extracted values are both empty counts as an exact match only.
+type counts struct {
+	truePositives  int
+	falsePositives int
+	falseNegatives int
+	exact          bool
+}
+
+// fieldCounts are the counts of every field of a document, keyed by field name.
+type fieldCounts map[string]counts
+
+func scoreResume(expected, got model.CandidateResume) fieldCounts {
+	scores := fieldCounts{
+		"full_name":         compareValue(expected.FullName, got.FullName, sameValue),
+		"email":             compareValue(expected.Email, got.Email, sameValue),
+		"phone":             compareValue(expected.Phone, got.Phone, sameValue),
+		"description":       compareValue(expected.Description, got.Description, similarText),
+		"short_description": compareValue(expected.ShortDescription, got.ShortDescription, similarText),
+		"location":          compareValue(expected.Location, got.Location, sameValue),
+		"availability":      compareValue(expected.Availability, got.Availability, sameValue),
+		"remote_preference": compareValue(expected.RemotePreference, got.RemotePreference, sameValue),
+		"mission_duration":  compareValue(expected.MissionDuration, got.MissionDuration, sameValue),
+		"rate":              compareValue(expected.Rate.String(), got.Rate.String(), sameValue),
+		"billing_mode":      compareValue(expected.BillingMode, got.BillingMode, sameValue),
+		"certifications":    compareList(expected.Certifications, got.Certifications, sameValue),
+		"skills":            compareList(model.SkillNames(expected.Skills), model.SkillNames(got.Skills), sameValue),
+	}
+	scoreExperiences(scores, expected.Experiences, got.Experiences)
+	scoreEducation(scores, expected.Education, got.Education)
+	scoreLanguages(scores, "languages", expected.Languages, got.Languages)
+	return scores
+}
+
+// scoreExperiences pairs experiences by company and job title, then compares the other fields of
+// the paired experiences only.
+func scoreExperiences(scores fieldCounts, expected, got []model.Experience) {
+	experienceKey := func(e model.Experience) string {
+		return normalize(e.CompanyName) + "|" + normalize(e.JobTitle)
+	}
+	expectedKeys := make([]string, len(expected))
+	for i, e := range expected {
+		expectedKeys[i] = experienceKey(e)
+	}
+	gotKeys := make([]string, len(got))
+	for i, e := range got {
+		gotKeys[i] = experienceKey(e)
+	}
+	scores["experiences"] = compareList(expectedKeys, gotKeys, sameValue)
+
+	dates, descriptions, tools := counts{exact: true}, counts{exact: true}, counts{exact: true}
+	for _, pair := range pairItems(expectedKeys, gotKeys, sameValue) {
+		e, g := expected[pair[0]], got[pair[1]]
+		dates = dates.add(compareValue(e.Dates, g.Dates, sameValue))
+		descriptions = descriptions.add(compareValue(e.Description, g.Description, similarText))
+		tools = tools.add(compareList(splitTools(e.Tools), splitTools(g.Tools), sameValue))
+	}
+	scores["experiences.dates"] = dates
+	scores["experiences.description"] = descriptions
+	scores["experiences.tools"] = tools
+}
+
+// scoreEducation pairs education entries by degree and school, then compares their field and
+// year.
+func scoreEducation(scores fieldCounts, expected, got []model.Education) {
+	educationKeys := func(educations []model.Education) []string {
+		keys := make([]string, len(educations))
+		for i, e := range educations {
+			keys[i] = normalize(e.Degree) + "|" + normalize(e.School)
+		}
+		return keys
+	}
+	expectedKeys, gotKeys := educationKeys(expected), educationKeys(got)
+	scores["education"] = compareList(expectedKeys, gotKeys, sameValue)
+
+	fields, years := counts{exact: true}, counts{exact: true}
+	for _, pair := range pairItems(expectedKeys, gotKeys, sameValue) {
+		e, g := expected[pair[0]], got[pair[1]]
+		fields = fields.add(compareValue(e.Field, g.Field, similarText))
+		years = years.add(compareValue(e.Year, g.Year, sameValue))
+	}
+	scores["education.field"] = fields
+	scores["education.year"] = years
+}
+
+// scoreLanguages pairs the languages of field by name, then compares their CEFR levels.
+func scoreLanguages(scores fieldCounts, field string, expected, got []model.Language) {
+	languageNames := func(languages []model.Language) []string {
+		names := make([]string, len(languages))
+		for i, l := range languages {
+			names[i] = l.Name
+		}
+		return names
+	}
+	expectedNames, gotNames := languageNames(expected), languageNames(got)
+	scores[field] = compareList(expectedNames, gotNames, sameValue)
+
+	levels := counts{exact: true}
+	for _, pair := range pairItems(expectedNames, gotNames, sameValue) {
+		levels = levels.add(compareValue(expected[pair[0]].Level, got[pair[1]].Level, sameValue))
+	}
+	scores[field+".level"] = levels
+}
+
+func scoreJobAd(expected, got model.JobAd) fieldCounts {
+	scores := fieldCounts{
+		"title":                    compareValue(expected.Title, got.Title, sameValue),
+		"company_name":             compareValue(expected.CompanyName, got.CompanyName, sameValue),
+		"location":                 compareValue(expected.Location, got.Location, sameValue),
+		"key_responsibilities":     compareList(expected.KeyResponsibilities, got.KeyResponsibilities, similarText),
+		"required_qualifications":  compareList(expected.RequiredQualifications, got.RequiredQualifications, similarText),
+		"preferred_qualifications": compareList(expected.PreferredQualifications, got.PreferredQualifications, similarText),
+		"skills":                   compareList(expected.Skills, got.Skills, sameValue),
+		"contract_type":            compareValue(expected.ContractType, got.ContractType, sameValue),
+		"seniority":                compareValue(expected.Seniority, got.Seniority, sameValue),
+		"min_years_of_experience":  compareValue(formatNumber(expected.MinYearsOfExperience), formatNumber(got.MinYearsOfExperience), sameValue),
+		"remote_policy":            compareValue(expected.RemotePolicy, got.RemotePolicy, sameValue),
+		"start_date":               compareValue(expected.StartDate, got.StartDate, sameValue),
+		"duration":                 compareValue(expected.Duration, got.Duration, sameValue),
+		"budget":                   compareValue(expected.Budget.String(), got.Budget.String(), sameValue),
+		"positions":                compareValue(formatNumber(float64(expected.Positions)), formatNumber(float64(got.Positions)), sameValue),
+	}
+	scoreLanguages(scores, "required_languages", expected.RequiredLanguages, got.RequiredLanguages)
+	return scores
+}
+
+// formatNumber formats a number for compareValue, a zero being an empty value.
+func formatNumber(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func compareValue(expected, got string, match func(a, b string) bool) counts {
+	expectedEmpty, gotEmpty := normalize(expected) == "", normalize(got) == ""
+	switch {
+	case expectedEmpty && gotEmpty:
+		return counts{exact: true}
+	case expectedEmpty:
+		return counts{falsePositives: 1}
+	case gotEmpty:
+		return counts{falseNegatives: 1}
+	case match(expected, got):
+		return counts{truePositives: 1, exact: true}
+	default:
+		return counts{falsePositives: 1, falseNegatives: 1}
+	}
+}
+
+// compareList counts the extracted items matching an expected one, each expected item matching
+// at most one extracted item.
+func compareList(expected, got []string, match func(a, b string) bool) counts {
+	expected, got = nonEmpty(expected), nonEmpty(got)
+	matched := len(pairItems(expected, got, match))
+	c := counts{
+		truePositives:  matched,
+		falsePositives: len(got) - matched,
+		falseNegatives: len(expected) - matched,
+	}
+	c.exact = c.falsePositives == 0 && c.falseNegatives == 0
+	return c
+}
+
+// pairItems greedily pairs each extracted item with the first unpaired expected item it matches,
+// and returns the [expected, extracted] index pairs.
+func pairItems(expected, got []string, match func(a, b string) bool) [][2]int {
+	used := make([]bool, len(expected))
+	var pairs [][2]int
+	for j, g := range got {
+		for i, e := range expected {
+			if !used[i] && match(e, g) {
+				used[i] = true
+				pairs = append(pairs, [2]int{i, j})
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+func (c counts) add(other counts) counts {
+	return counts{
+		truePositives:  c.truePositives + other.truePositives,
+		falsePositives: c.falsePositives + other.falsePositives,
+		falseNegatives: c.falseNegatives + other.falseNegatives,
+		exact:          c.exact && other.exact,
+	}
+}
+
+func sameValue(a, b string) bool {
+	return normalize(a) == normalize(b)
+}
+
+// similarText reports whether two texts share at least minTextSimilarity of their words.
+func similarText(a, b string) bool {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return len(wordsA) == len(wordsB)
+	}
+	common := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			common++
+		}
+	}
+	return float64(common)/float64(len(wordsA)+len(wordsB)-common) >= minTextSimilarity
+}
+
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(normalize(text)) {
+		words[word] = true
+	}
+	return words
+}
+
+// normalize lowercases value and reduces punctuation and spacing to single spaces.
+func normalize(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#", r)
+	}), " ")
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, value := range values {
+		if normalize(value) != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func splitTools(tools string) []string {
+	if strings.TrimSpace(tools) == "" {
+		return nil
+	}
+	return toolSeparators.Split(strings.TrimSpace(tools), -1)
+}
@@ -0,0 +1,128 @@
+package eval
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestCompareValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		expected, got string
+		want          counts
+	}{
+		{"both empty", "", " - ", counts{exact: true}},
+		{"same value", "Jean Dupont", "jean  dupont.", counts{truePositives: 1, exact: true}},
+		{"extra value", "", "Paris", counts{falsePositives: 1}},
+		{"missing value", "Paris", "", counts{falseNegatives: 1}},
+		{"wrong value", "Paris", "Lyon", counts{falsePositives: 1, falseNegatives: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareValue(tt.expected, tt.got, sameValue); got != tt.want {
+				t.Errorf("compareValue(%q, %q) = %+v, want %+v", tt.expected, tt.got, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareList(t *testing.T) {
+	tests := []struct {
+		name          string
+		expected, got []string
+		want          counts
+	}{
+		{"both empty", nil, []string{"", " "}, counts{exact: true}},
+		{"same items in another order", []string{"Go", "SQL"}, []string{"sql", "go"}, counts{truePositives: 2, exact: true}},
+		{"missing and extra items", []string{"Go", "SQL", "Docker"}, []string{"Go", "Java"},
+			counts{truePositives: 1, falsePositives: 1, falseNegatives: 2}},
+		{"duplicate extracted item matches once", []string{"Go"}, []string{"Go", "go"},
+			counts{truePositives: 1, falsePositives: 1}},
+		{"duplicate expected items", []string{"Go", "Go"}, []string{"Go"}, counts{truePositives: 1, falseNegatives: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareList(tt.expected, tt.got, sameValue); got != tt.want {
+				t.Errorf("compareList(%q, %q) = %+v, want %+v", tt.expected, tt.got, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairItems(t *testing.T) {
+	tests := []struct {
+		name          string
+		expected, got []string
+		match         func(a, b string) bool
+		want          [][2]int
+	}{
+		{"in order", []string{"a", "b"}, []string{"a", "b"}, sameValue, [][2]int{{0, 0}, {1, 1}}},
+		{"reordered", []string{"a", "b"}, []string{"b", "a"}, sameValue, [][2]int{{1, 0}, {0, 1}}},
+		{"unmatched items", []string{"a", "b"}, []string{"c", "b"}, sameValue, [][2]int{{1, 1}}},
+		{"each expected item is paired once", []string{"a"}, []string{"a", "a"}, sameValue, [][2]int{{0, 0}}},
+		{"similar texts", []string{"Conception de l'API de paiement", "Migration vers Kubernetes"},
+			[]string{"Migration des services vers Kubernetes", "conception API paiement"}, similarText, [][2]int{{1, 0}, {0, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pairItems(tt.expected, tt.got, tt.match); !slices.Equal(got, tt.want) {
+				t.Errorf("pairItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarText(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Développement d'une API REST en Go", "développement API REST Go", true},
+		{"Développement d'une API REST en Go", "Maintenance d'un site PHP", false},
+		{"", "  ", true},
+		{"Go", "", false},
+	}
+	for _, tt := range tests {
+		if got := similarText(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarText(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScoreResume(t *testing.T) {
+	expected := model.CandidateResume{
+		FullName: "Jean Dupont",
+		Experiences: []model.Experience{
+			{CompanyName: "Acme", JobTitle: "Développeur", Dates: "2019 - 2021", Tools: "Go, PostgreSQL"},
+			{CompanyName: "Globex", JobTitle: "Stagiaire", Dates: "2018"},
+		},
+		Languages: []model.Language{{Name: "Anglais", Level: "B2"}},
+	}
+	got := model.CandidateResume{
+		FullName: "Jean Dupont",
+		Email:    "jean@example.com",
+		Experiences: []model.Experience{
+			{CompanyName: "ACME", JobTitle: "développeur", Dates: "2019 - 2022", Tools: "Go / Docker"},
+		},
+		Languages: []model.Language{{Name: "anglais", Level: "B2"}},
+	}
+	scores := scoreResume(expected, got)
+	want := map[string]counts{
+		"full_name":               {truePositives: 1, exact: true},
+		"email":                   {falsePositives: 1},
+		"skills":                  {exact: true},
+		"experiences":             {truePositives: 1, falseNegatives: 1},
+		"experiences.dates":       {falsePositives: 1, falseNegatives: 1},
+		"experiences.tools":       {truePositives: 1, falsePositives: 1, falseNegatives: 1},
+		"experiences.description": {exact: true},
+		"languages":               {truePositives: 1, exact: true},
+		"languages.level":         {truePositives: 1, exact: true},
+	}
+	for field, want := range want {
+		if scores[field] != want {
+			t.Errorf("%s = %+v, want %+v", field, scores[field], want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import "net/http"
+
+func (h *Handler) listProviderHealth(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.providers.ProviderHealth())
+}
+
+func (h *Handler) listBudgets(w http.ResponseWriter, r *http.Request) {
+	statuses, err := h.synthesizer.BudgetStatuses(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, statuses)
+}
+
+type budgetLimitsRequest struct {
+	SoftLimitUSD float64 `json:"soft_limit_usd"`
+	HardLimitUSD float64 `json:"hard_limit_usd"`
+}
+
+func (h *Handler) updateBudget(w http.ResponseWriter, r *http.Request) {
+	var req budgetLimitsRequest
+	if err := h.readJSON(r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	budget, err := h.synthesizer.UpdateBudgetLimits(r.Context(), r.PathValue("name"), req.SoftLimitUSD, req.HardLimitUSD)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, budgetLimitsRequest{
+		SoftLimitUSD: budget.SoftLimitUSD,
+		HardLimitUSD: budget.HardLimitUSD,
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func (h *Handler) listCandidates(w http.ResponseWriter, r *http.Request) {
+	candidates, err := h.synthesizer.ListCandidates(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, candidates)
+}
+
+func (h *Handler) getCandidate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	candidate, err := h.synthesizer.GetCandidate(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, candidate)
+}
+
+type candidateRequest struct {
+	FullName     string      `json:"full_name"`
+	Emails       []string    `json:"emails"`
+	Phones       []string    `json:"phones"`
+	Rate         *model.Rate `json:"rate"`
+	BillingMode  string      `json:"billing_mode"`
+	Availability string      `json:"availability"`
+	Notes        string      `json:"notes"`
+}
+
+// updateCandidate replaces the name, contact details and internal data of a candidate.
+func (h *Handler) updateCandidate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	var req candidateRequest
+	if err := h.readJSON(r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	candidate, err := h.synthesizer.UpdateCandidate(r.Context(), model.Candidate{
+		ID:           id,
+		FullName:     req.FullName,
+		Emails:       req.Emails,
+		Phones:       req.Phones,
+		Rate:         req.Rate,
+		BillingMode:  req.BillingMode,
+		Availability: req.Availability,
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, candidate)
+}
+
+type mergeCandidateRequest struct {
+	CandidateID int `json:"candidate_id"`
+}
+
+// mergeCandidate merges the candidate given in the body into the candidate of the path.
+func (h *Handler) mergeCandidate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	var req mergeCandidateRequest
+	if err := h.readJSON(r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	candidate, err := h.synthesizer.MergeCandidates(r.Context(), id, req.CandidateID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, candidate)
+}
+
+// dismissDuplicate records that the candidate of the path and the possible duplicate
+// {other} are different people.
+func (h *Handler) dismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	otherID, err := strconv.Atoi(r.PathValue("other"))
+	if err != nil {
+		h.writeError(w, fmt.Errorf("%w: other must be an integer", service.ErrInvalidArgument))
+		return
+	}
+	candidate, err := h.synthesizer.DismissDuplicate(r.Context(), id, otherID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, candidate)
+}
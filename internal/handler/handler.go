@@ -1,7 +1,110 @@
 package handler
 
-import "net/http"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 
-func New() http.Handler {
+	"github.com/mfreyr/deckgen/internal/middleware"
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+	"github.com/rs/zerolog"
+)
+
+// ProviderHealthReporter exposes the health of the configured LLM providers.
+type ProviderHealthReporter interface {
+	ProviderHealth() []model.ProviderHealth
+}
+
+// PromptLister exposes the prompts currently in use.
+type PromptLister interface {
+	Prompts() []model.PromptInfo
+}
+
+type Handler struct {
+	logger      zerolog.Logger
+	synthesizer *service.SynthesizerService
+	providers   ProviderHealthReporter
+	prompts     PromptLister
+}
+
+func New(logger zerolog.Logger, synthesizer *service.SynthesizerService, providers ProviderHealthReporter, prompts PromptLister) http.Handler {
+	h := &Handler{
+		logger:      logger,
+		synthesizer: synthesizer,
+		providers:   providers,
+		prompts:     prompts,
+	}
+
+	admin := func(handler http.HandlerFunc) http.Handler {
+		return middleware.RequireAdmin(handler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /admin/providers", admin(h.listProviderHealth))
+	mux.Handle("GET /admin/budgets", admin(h.listBudgets))
+	mux.Handle("PUT /admin/budgets/{name}", admin(h.updateBudget))
+	mux.Handle("GET /usage/report", admin(h.usageReport))
+	mux.HandleFunc("GET /prompts", h.listPrompts)
+	mux.HandleFunc("GET /taxonomy", h.listTaxonomy)
+	mux.HandleFunc("GET /taxonomy/unmapped", h.listUnmappedTerms)
+	mux.Handle("PUT /taxonomy/terms/{name}", admin(h.putTaxonomyTerm))
+	mux.Handle("DELETE /taxonomy/terms/{name}", admin(h.deleteTaxonomyTerm))
+
+	mux.HandleFunc("POST /resumes", h.parseResume)
+	mux.HandleFunc("GET /resumes", h.listResumes)
+	mux.HandleFunc("GET /resumes/{id}", h.getResume)
+	mux.HandleFunc("GET /resumes/{id}/provenance", h.resumeProvenance)
+	mux.HandleFunc("GET /candidates", h.listCandidates)
+	mux.HandleFunc("GET /candidates/{id}", h.getCandidate)
+	mux.HandleFunc("PUT /candidates/{id}", h.updateCandidate)
+	mux.HandleFunc("POST /candidates/{id}/merge", h.mergeCandidate)
+	mux.HandleFunc("DELETE /candidates/{id}/duplicates/{other}", h.dismissDuplicate)
+	mux.HandleFunc("POST /job-ads", h.parseJobAd)
+	mux.HandleFunc("GET /job-ads", h.listJobAds)
+	mux.HandleFunc("GET /job-ads/{id}", h.getJobAd)
+	mux.HandleFunc("GET /job-ads/{id}/provenance", h.jobAdProvenance)
+	mux.HandleFunc("GET /job-ads/{id}/matches", h.matchJobAd)
+	return mux
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Error().Err(err).Msg("failed to write json response")
+	}
+}
+
+func (h *Handler) readJSON(r *http.Request, body any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		return fmt.Errorf("%w: invalid json body: %w", service.ErrInvalidArgument, err)
+	}
 	return nil
 }
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalidArgument):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrBudgetExceeded):
+		status = http.StatusPaymentRequired
+	case errors.Is(err, service.ErrProviderUnavailable):
+		status = http.StatusServiceUnavailable
+	default:
+		var llmErr *service.LLMError
+		if errors.As(err, &llmErr) {
+			status = http.StatusBadGateway
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		h.logger.Error().Err(err).Msg("request failed")
+	}
+	h.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func (h *Handler) parseJobAd(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseOptions(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	file, err := readUpload(w, r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	jobAd, err := h.synthesizer.ParseJobAd(r.Context(), file, opts)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, jobAd)
+}
+
+// listJobAds lists every job ad, or only those having a field below the max_confidence query
+// parameter and matching the parameters read by parseJobAdFilter when they are given.
+func (h *Handler) listJobAds(w http.ResponseWriter, r *http.Request) {
+	maxConfidence, err := parseConfidenceParam(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	filter, err := parseJobAdFilter(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	jobAds, err := h.synthesizer.ListJobAds(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if maxConfidence > 0 {
+		jobAds = slices.DeleteFunc(jobAds, func(jobAd model.JobAd) bool {
+			return len(service.LowConfidenceFields(jobAd.Provenance, maxConfidence)) == 0
+		})
+	}
+	jobAds = slices.DeleteFunc(jobAds, func(jobAd model.JobAd) bool {
+		return !filter.Matches(jobAd)
+	})
+	h.writeJSON(w, http.StatusOK, jobAds)
+}
+
+// parseJobAdFilter reads the contract_type, seniority, remote_policy and language parameters,
+// the daily_rate parameter in the base currency, and the start_by parameter as YYYY-MM.
+func parseJobAdFilter(r *http.Request) (service.JobAdFilter, error) {
+	query := r.URL.Query()
+	filter := service.JobAdFilter{
+		ContractType: query.Get("contract_type"),
+		Seniority:    query.Get("seniority"),
+		RemotePolicy: query.Get("remote_policy"),
+		Language:     query.Get("language"),
+	}
+	for _, param := range []struct {
+		name   string
+		value  string
+		values []string
+	}{
+		{"contract_type", filter.ContractType, model.ContractTypes},
+		{"seniority", filter.Seniority, model.Seniorities},
+		{"remote_policy", filter.RemotePolicy, model.RemotePolicies},
+	} {
+		if param.value != "" && !slices.Contains(param.values, param.value) {
+			return filter, fmt.Errorf("%w: %s must be one of %s",
+				service.ErrInvalidArgument, param.name, strings.Join(param.values, ", "))
+		}
+	}
+	if value := query.Get("daily_rate"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return filter, fmt.Errorf("%w: daily_rate must be a positive number", service.ErrInvalidArgument)
+		}
+		filter.DailyRate = rate
+	}
+	if value := query.Get("start_by"); value != "" {
+		start, err := time.Parse("2006-01", value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: start_by must be formatted as YYYY-MM", service.ErrInvalidArgument)
+		}
+		filter.StartBy = &model.YearMonth{Year: start.Year(), Month: int(start.Month())}
+	}
+	return filter, nil
+}
+
+// matchJobAd lists every resume with how it fits the job ad, the best fits first.
+func (h *Handler) matchJobAd(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	matches, err := h.synthesizer.MatchResumes(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, matches)
+}
+
+func (h *Handler) getJobAd(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	jobAd, err := h.synthesizer.GetJobAd(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, jobAd)
+}
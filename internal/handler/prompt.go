@@ -0,0 +1,7 @@
+package handler
+
+import "net/http"
+
+func (h *Handler) listPrompts(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.prompts.Prompts())
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// resumeProvenance returns where each field of a resume was found in its source document,
+// restricted to the fields below the max_confidence query parameter when it is given.
+func (h *Handler) resumeProvenance(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	maxConfidence, err := parseConfidenceParam(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	resume, err := h.synthesizer.GetResume(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, filterProvenance(resume.Provenance, maxConfidence))
+}
+
+// jobAdProvenance is resumeProvenance for job ads.
+func (h *Handler) jobAdProvenance(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	maxConfidence, err := parseConfidenceParam(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	jobAd, err := h.synthesizer.GetJobAd(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, filterProvenance(jobAd.Provenance, maxConfidence))
+}
+
+// parseConfidenceParam reads the max_confidence query parameter, zero meaning no filter.
+func parseConfidenceParam(r *http.Request) (float64, error) {
+	value := r.URL.Query().Get("max_confidence")
+	if value == "" {
+		return 0, nil
+	}
+	confidence, err := strconv.ParseFloat(value, 64)
+	if err != nil || confidence <= 0 || confidence > 1 {
+		return 0, fmt.Errorf("%w: max_confidence must be a number between 0 and 1", service.ErrInvalidArgument)
+	}
+	return confidence, nil
+}
+
+func filterProvenance(provenance []model.FieldProvenance, maxConfidence float64) []model.FieldProvenance {
+	if maxConfidence == 0 {
+		return provenance
+	}
+	fields := service.LowConfidenceFields(provenance, maxConfidence)
+	if fields == nil {
+		return []model.FieldProvenance{}
+	}
+	return fields
+}
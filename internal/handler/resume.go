@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func (h *Handler) parseResume(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseOptions(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	file, err := readUpload(w, r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	resume, err := h.synthesizer.ParseResume(r.Context(), file, opts)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, resume)
+}
+
+// listResumes lists every resume, or only those having a field below the max_confidence query
+// parameter, a skill matching the skill, skill_category and min_years parameters, and a daily
+// rate and billing mode matching the max_daily_rate and billing_mode parameters when they are
+// given.
+func (h *Handler) listResumes(w http.ResponseWriter, r *http.Request) {
+	maxConfidence, err := parseConfidenceParam(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	skillFilter, err := parseSkillFilter(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	skillFilter.Name = h.synthesizer.CanonicalSkill(skillFilter.Name)
+	rateFilter, err := parseRateFilter(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	resumes, err := h.synthesizer.ListResumes(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if maxConfidence > 0 {
+		resumes = slices.DeleteFunc(resumes, func(resume model.CandidateResume) bool {
+			return len(service.LowConfidenceFields(resume.Provenance, maxConfidence)) == 0
+		})
+	}
+	resumes = slices.DeleteFunc(resumes, func(resume model.CandidateResume) bool {
+		return !skillFilter.Matches(resume) || !rateFilter.Matches(resume)
+	})
+	h.writeJSON(w, http.StatusOK, resumes)
+}
+
+// parseRateFilter reads the max_daily_rate parameter, in the base currency, and the billing_mode
+// parameter.
+func parseRateFilter(r *http.Request) (service.RateFilter, error) {
+	query := r.URL.Query()
+	filter := service.RateFilter{BillingMode: query.Get("billing_mode")}
+	if filter.BillingMode != "" && !slices.Contains(model.BillingModes, filter.BillingMode) {
+		return filter, fmt.Errorf("%w: billing_mode must be one of %s",
+			service.ErrInvalidArgument, strings.Join(model.BillingModes, ", "))
+	}
+	if value := query.Get("max_daily_rate"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return filter, fmt.Errorf("%w: max_daily_rate must be a positive number", service.ErrInvalidArgument)
+		}
+		filter.MaxDailyRate = rate
+	}
+	return filter, nil
+}
+
+func parseSkillFilter(r *http.Request) (service.SkillFilter, error) {
+	query := r.URL.Query()
+	filter := service.SkillFilter{Name: query.Get("skill"), Category: query.Get("skill_category")}
+	if filter.Category != "" && !slices.Contains(model.SkillCategories, filter.Category) {
+		return filter, fmt.Errorf("%w: skill_category must be one of %s",
+			service.ErrInvalidArgument, strings.Join(model.SkillCategories, ", "))
+	}
+	if value := query.Get("min_years"); value != "" {
+		years, err := strconv.ParseFloat(value, 64)
+		if err != nil || years < 0 {
+			return filter, fmt.Errorf("%w: min_years must be a positive number", service.ErrInvalidArgument)
+		}
+		filter.MinYears = years
+	}
+	return filter, nil
+}
+
+func (h *Handler) getResume(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	resume, err := h.synthesizer.GetResume(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, resume)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/mfreyr/deckgen/internal/taxonomy"
+)
+
+func (h *Handler) listTaxonomy(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.synthesizer.TaxonomyTerms())
+}
+
+// listUnmappedTerms lists the skills and tools met while parsing that the taxonomy does not
+// know, the most frequent first.
+func (h *Handler) listUnmappedTerms(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.synthesizer.UnmappedTerms())
+}
+
+type taxonomyTermRequest struct {
+	Category string   `json:"category"`
+	Parent   string   `json:"parent"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// putTaxonomyTerm adds or replaces the term named in the path.
+func (h *Handler) putTaxonomyTerm(w http.ResponseWriter, r *http.Request) {
+	var req taxonomyTermRequest
+	if err := h.readJSON(r, &req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	term, err := h.synthesizer.PutTaxonomyTerm(taxonomy.Term{
+		Name:     r.PathValue("name"),
+		Category: req.Category,
+		Parent:   req.Parent,
+		Synonyms: req.Synonyms,
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, term)
+}
+
+func (h *Handler) deleteTaxonomyTerm(w http.ResponseWriter, r *http.Request) {
+	if err := h.synthesizer.DeleteTaxonomyTerm(r.PathValue("name")); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
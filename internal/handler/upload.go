@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+const maxUploadBytes = 20 << 20
+
+// readUpload reads the "file" part of a multipart upload.
+func readUpload(w http.ResponseWriter, r *http.Request) (model.File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	part, header, err := r.FormFile("file")
+	if err != nil {
+		return model.File{}, fmt.Errorf("%w: a 'file' multipart field is required: %w", service.ErrInvalidArgument, err)
+	}
+	defer part.Close()
+
+	content, err := io.ReadAll(part)
+	if err != nil {
+		return model.File{}, fmt.Errorf("%w: failed to read uploaded file: %w", service.ErrInvalidArgument, err)
+	}
+	if len(content) == 0 {
+		return model.File{}, fmt.Errorf("%w: uploaded file is empty", service.ErrInvalidArgument)
+	}
+	return model.File{
+		Name:      header.Filename,
+		Extension: strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), ".")),
+		MimeType:  header.Header.Get("Content-Type"),
+		Content:   content,
+	}, nil
+}
+
+// parseOptions reads the provider and force_refresh query parameters.
+func parseOptions(r *http.Request) (service.ParseOptions, error) {
+	query := r.URL.Query()
+	opts := service.ParseOptions{Provider: service.LLMProviderName(query.Get("provider"))}
+	if value := query.Get("force_refresh"); value != "" {
+		forceRefresh, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("%w: force_refresh must be a boolean", service.ErrInvalidArgument)
+		}
+		opts.ForceRefresh = forceRefresh
+	}
+	return opts, nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: id must be an integer", service.ErrInvalidArgument)
+	}
+	return id, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// usageReport aggregates usage by the group_by query parameter, optionally restricted to the
+// [from, to) date range given as YYYY-MM-DD.
+func (h *Handler) usageReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = "day"
+	}
+	from, err := parseDateParam(query.Get("from"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	to, err := parseDateParam(query.Get("to"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	report, err := h.synthesizer.UsageReport(r.Context(), groupBy, from, to)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date '%s' must use the YYYY-MM-DD format", service.ErrInvalidArgument, value)
+	}
+	return date, nil
+}
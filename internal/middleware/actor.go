@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// APIKey is a key accepted in the Authorization header and the actor it authenticates.
+type APIKey struct {
+	Key   string
+	Actor service.Actor
+}
+
+// Actor authenticates the caller by the bearer API key of the Authorization header, and stores
+// the actor of the key in the request context so that usage and budgets are attributed to it.
+// Requests without a known key are refused, unless no key is configured, in which case every
+// request is made by the anonymous actor.
+func Actor(keys []APIKey, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(keys) == 0 {
+			next.ServeHTTP(w, r.WithContext(service.WithActor(r.Context(), service.Actor{})))
+			return
+		}
+		actor, ok := authenticate(keys, r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="deckgen"`)
+			writeError(w, http.StatusUnauthorized, "a valid API key is required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(service.WithActor(r.Context(), actor)))
+	})
+}
+
+// RequireAdmin refuses the requests of actors that are not admins.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !service.ActorFromContext(r.Context()).Admin {
+			writeError(w, http.StatusForbidden, "an admin API key is required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the actor of the bearer key of header, comparing keys in constant time.
+func authenticate(keys []APIKey, header string) (service.Actor, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return service.Actor{}, false
+	}
+	token = strings.TrimSpace(token)
+	var actor service.Actor
+	found := false
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			actor, found = key.Actor, true
+		}
+	}
+	return actor, found
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+func TestActor(t *testing.T) {
+	keys := []APIKey{
+		{Key: "tenant-key-0123456789abcdef", Actor: service.Actor{TenantID: "acme", UserID: "alice"}},
+		{Key: "admin-key-0123456789abcdef", Actor: service.Actor{TenantID: "ops", Admin: true}},
+	}
+	tests := []struct {
+		name          string
+		keys          []APIKey
+		authorization string
+		headers       map[string]string
+		wantStatus    int
+		wantActor     service.Actor
+	}{
+		{"valid key", keys, "Bearer tenant-key-0123456789abcdef", nil, http.StatusOK, service.Actor{TenantID: "acme", UserID: "alice"}},
+		{"lowercase scheme", keys, "bearer admin-key-0123456789abcdef", nil, http.StatusOK, service.Actor{TenantID: "ops", Admin: true}},
+		{"identity headers are ignored", keys, "Bearer tenant-key-0123456789abcdef", map[string]string{"X-Tenant-Id": "globex", "X-User-Id": "mallory"},
+			http.StatusOK, service.Actor{TenantID: "acme", UserID: "alice"}},
+		{"missing key", keys, "", nil, http.StatusUnauthorized, service.Actor{}},
+		{"unknown key", keys, "Bearer guessed", nil, http.StatusUnauthorized, service.Actor{}},
+		{"basic scheme", keys, "Basic tenant-key-0123456789abcdef", nil, http.StatusUnauthorized, service.Actor{}},
+		{"no keys configured", nil, "", map[string]string{"X-Tenant-Id": "globex"}, http.StatusOK, service.Actor{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got service.Actor
+			handler := Actor(tt.keys, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = service.ActorFromContext(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/resumes", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus || got != tt.wantActor {
+				t.Errorf("status %d with actor %+v, want %d with %+v", w.Code, got, tt.wantStatus, tt.wantActor)
+			}
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	handler := RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, tt := range []struct {
+		actor service.Actor
+		want  int
+	}{
+		{service.Actor{TenantID: "ops", Admin: true}, http.StatusOK},
+		{service.Actor{TenantID: "acme"}, http.StatusForbidden},
+		{service.Actor{}, http.StatusForbidden},
+	} {
+		r := httptest.NewRequest(http.MethodPut, "/admin/budgets/per-tenant", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.WithContext(service.WithActor(r.Context(), tt.actor)))
+		if w.Code != tt.want {
+			t.Errorf("actor %+v got status %d, want %d", tt.actor, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/service"
+)
+
+// Locale stores the locale requested by the "locale" query parameter, or else by the first
+// language of the Accept-Language header, in the request context.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := r.URL.Query().Get("locale")
+		if locale == "" {
+			locale = primaryLanguage(r.Header.Get("Accept-Language"))
+		}
+		if locale != "" {
+			r = r.WithContext(service.WithLocale(r.Context(), strings.ToLower(locale)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// primaryLanguage returns "fr" for "fr-FR,fr;q=0.9,en;q=0.8".
+func primaryLanguage(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	first, _, _ = strings.Cut(first, ";")
+	language, _, _ := strings.Cut(strings.TrimSpace(first), "-")
+	if language == "*" {
+		return ""
+	}
+	return language
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minPlausibleYear is the earliest year accepted in resume dates.
+const minPlausibleYear = 1950
+
+var yearPattern = regexp.MustCompile(`\b\d{4}\b`)
+
+// CheckResume applies the rules a parsed resume must follow beyond its schema.
+func CheckResume(resume CandidateResume) []string {
+	var problems []string
+	if strings.TrimSpace(resume.FullName) == "" {
+		problems = append(problems, "$.full_name: the candidate name must not be empty")
+	}
+	return append(problems, CheckResumePart(resume)...)
+}
+
+// CheckResumePart applies the rules of CheckResume that also hold for a part of a resume.
+func CheckResumePart(resume CandidateResume) []string {
+	var problems []string
+	for i, experience := range resume.Experiences {
+		path := fmt.Sprintf("$.experiences[%d]", i)
+		if strings.TrimSpace(experience.CompanyName) == "" && strings.TrimSpace(experience.JobTitle) == "" {
+			problems = append(problems, path+": an experience needs a company name or a job title")
+		}
+		if problem := checkDates(experience.Dates); problem != "" {
+			problems = append(problems, path+".dates: "+problem)
+		}
+	}
+	for i, skill := range resume.Skills {
+		if strings.TrimSpace(skill.Name) == "" {
+			problems = append(problems, fmt.Sprintf("$.skills[%d].name: the skill name must not be empty", i))
+		}
+	}
+	for i, education := range resume.Education {
+		if strings.TrimSpace(education.Degree) == "" && strings.TrimSpace(education.School) == "" {
+			problems = append(problems, fmt.Sprintf("$.education[%d]: an education needs a degree or a school", i))
+		}
+	}
+	if resume.Rate.Amount < 0 {
+		problems = append(problems, "$.rate.amount: the amount must not be negative")
+	}
+	for i, language := range resume.Languages {
+		path := fmt.Sprintf("$.languages[%d]", i)
+		if strings.TrimSpace(language.Name) == "" {
+			problems = append(problems, path+".name: the language name must not be empty")
+		}
+		if language.Level != "" && CEFRRank(language.Level) == 0 {
+			problems = append(problems, fmt.Sprintf("%s.level: %q is not a CEFR level, use one of %s or an empty string",
+				path, language.Level, strings.Join(CEFRLevels, ", ")))
+		}
+	}
+	return problems
+}
+
+// CheckJobAd applies the rules a parsed job ad must follow beyond its schema.
+func CheckJobAd(jobAd JobAd) []string {
+	var problems []string
+	if strings.TrimSpace(jobAd.Title) == "" {
+		problems = append(problems, "$.title: the job title must not be empty")
+	}
+	if jobAd.Budget.Min < 0 || jobAd.Budget.Max < 0 {
+		problems = append(problems, "$.budget: the amounts must not be negative")
+	}
+	if jobAd.Positions < 0 {
+		problems = append(problems, "$.positions: the number of positions must not be negative")
+	}
+	for i, language := range jobAd.RequiredLanguages {
+		if language.Level != "" && CEFRRank(language.Level) == 0 {
+			problems = append(problems, fmt.Sprintf("$.required_languages[%d].level: %q is not a CEFR level, use one of %s or an empty string",
+				i, language.Level, strings.Join(CEFRLevels, ", ")))
+		}
+	}
+	return problems
+}
+
+// checkDates reports years that cannot belong to a career, or periods that end before they start.
+func checkDates(dates string) string {
+	maxYear := time.Now().Year() + 1
+	var years []int
+	for _, match := range yearPattern.FindAllString(dates, -1) {
+		year, err := strconv.Atoi(match)
+		if err != nil {
+			continue
+		}
+		if year < minPlausibleYear || year > maxYear {
+			return fmt.Sprintf("year %d in %q is not plausible", year, dates)
+		}
+		years = append(years, year)
+	}
+	if len(years) >= 2 && years[len(years)-1] < years[0] {
+		return fmt.Sprintf("%q ends before it starts", dates)
+	}
+	return ""
+}
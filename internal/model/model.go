@@ -1,21 +1,162 @@
 package model
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type File struct {
 	ID        int
 	Name      string
 	Extension string
+	MimeType  string
 	Content   []byte
+	// Text is the text extracted locally from Content, empty when none could be extracted.
+	Text string
+	// Part and Parts locate a chunk of a long document, Parts being zero for a whole document.
+	Part  int
+	Parts int
+}
+
+// SourceDocument is the text extracted locally from the file an entity was parsed from.
+type SourceDocument struct {
+	FileName  string            `json:"file_name"`
+	MimeType  string            `json:"mime_type"`
+	Extractor string            `json:"extractor"`
+	Text      string            `json:"text"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	OCRPages  []OCRPage         `json:"ocr_pages,omitempty"`
+	// PageOffsets holds the character offset in Text at which each page starts, when the
+	// document has pages.
+	PageOffsets []int `json:"page_offsets,omitempty"`
+	// HiddenText lists the text a reader of the document would not see, which is left out of Text.
+	HiddenText []HiddenText `json:"hidden_text,omitempty"`
+	Warnings   []string     `json:"warnings,omitempty"`
+}
+
+// HiddenText is text of a document rendered invisible, for instance in white or in a tiny font.
+type HiddenText struct {
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+	Page   int    `json:"page,omitempty"`
+}
+
+// SuspiciousContent is content of a document that may be trying to manipulate the models reading
+// it: hidden text, or text addressing instructions to a model.
+type SuspiciousContent struct {
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// OCRPage is the quality of the text recognized on a page, as the mean word confidence (0-100).
+type OCRPage struct {
+	Page       int     `json:"page"`
+	Confidence float64 `json:"confidence"`
+	LowQuality bool    `json:"low_quality"`
+}
+
+// FieldProvenance locates the value of a parsed field in the text of its source document. Span is
+// nil when the value was not found, which happens for values the model rephrased or inferred.
+// Confidence goes from 0 to 1 and accounts for how closely the value matched and for the OCR
+// quality of its page.
+type FieldProvenance struct {
+	Field      string    `json:"field"`
+	Value      string    `json:"value"`
+	Page       int       `json:"page,omitempty"`
+	Span       *TextSpan `json:"span,omitempty"`
+	Snippet    string    `json:"snippet,omitempty"`
+	Confidence float64   `json:"confidence"`
+}
+
+// TextSpan is a [Start, End) range of character offsets in SourceDocument.Text.
+type TextSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 type JobAd struct {
-	ID                      int      `json:"id"`
-	Title                   string   `json:"title"`
-	CompanyName             string   `json:"company_name"`
-	Location                string   `json:"location"`
-	KeyResponsibilities     []string `json:"key_responsibilities"`
-	RequiredQualifications  []string `json:"required_qualifications"`
-	PreferredQualifications []string `json:"preferred_qualifications"`
-	RawText                 string   `json:"raw_text"`
+	ID                      int        `json:"id" jsonschema:"-"`
+	Title                   string     `json:"title"`
+	CompanyName             string     `json:"company_name"`
+	Location                string     `json:"location"`
+	KeyResponsibilities     []string   `json:"key_responsibilities"`
+	RequiredQualifications  []string   `json:"required_qualifications"`
+	PreferredQualifications []string   `json:"preferred_qualifications"`
+	Skills                  []string   `json:"skills" jsonschema:"description=Technologies and tools and methods the job ad asks for"`
+	ContractType            string     `json:"contract_type" jsonschema:"description=One of freelance permanent fixed_term subcontracting or portage when the job ad states it or else an empty string"`
+	Seniority               string     `json:"seniority" jsonschema:"description=One of junior intermediate senior or expert when the job ad states it or else an empty string"`
+	MinYearsOfExperience    float64    `json:"min_years_of_experience" jsonschema:"description=Minimum years of experience asked for or 0 when not stated"`
+	RemotePolicy            string     `json:"remote_policy" jsonschema:"description=One of onsite hybrid or remote when the job ad states it or else an empty string"`
+	StartDate               string     `json:"start_date" jsonschema:"description=Start date as written such as ASAP or March 2026"`
+	Duration                string     `json:"duration" jsonschema:"description=Mission duration as written such as 6 months renewable"`
+	Budget                  RateRange  `json:"budget" jsonschema:"description=Budget or daily rate range of the mission"`
+	RequiredLanguages       []Language `json:"required_languages"`
+	Positions               int        `json:"positions" jsonschema:"description=Number of positions to fill or 1 when not stated"`
+	RawText                 string     `json:"raw_text"`
+	Provider                string     `json:"provider" jsonschema:"-"`
+	PromptVersion           string     `json:"prompt_version" jsonschema:"-"`
+
+	// StartASAP and StartMonth are parsed from StartDate, DurationMonths from Duration, and
+	// DailyBudget is Budget per day in the base currency, when it can be converted.
+	StartASAP      bool       `json:"start_asap,omitempty" jsonschema:"-"`
+	StartMonth     *YearMonth `json:"start_month,omitempty" jsonschema:"-"`
+	DurationMonths int        `json:"duration_months,omitempty" jsonschema:"-"`
+	DailyBudget    *RateRange `json:"daily_budget,omitempty" jsonschema:"-"`
+
+	Source     *SourceDocument   `json:"source,omitempty" jsonschema:"-"`
+	Provenance []FieldProvenance `json:"provenance,omitempty" jsonschema:"-"`
+	Warnings   []string          `json:"warnings,omitempty" jsonschema:"-"`
+	// Suspicious content makes the entity need a review before its fields are trusted.
+	Suspicious  []SuspiciousContent `json:"suspicious,omitempty" jsonschema:"-"`
+	NeedsReview bool                `json:"needs_review" jsonschema:"-"`
+}
+
+// Contract types of a job ad.
+const (
+	ContractFreelance      = "freelance"
+	ContractPermanent      = "permanent"
+	ContractFixedTerm      = "fixed_term"
+	ContractSubcontracting = "subcontracting"
+	ContractPortage        = "portage"
+)
+
+// ContractTypes lists the contract types.
+var ContractTypes = []string{ContractFreelance, ContractPermanent, ContractFixedTerm, ContractSubcontracting, ContractPortage}
+
+// Seniorities, from the lowest to the highest.
+const (
+	SeniorityJunior       = "junior"
+	SeniorityIntermediate = "intermediate"
+	SenioritySenior       = "senior"
+	SeniorityExpert       = "expert"
+)
+
+// Seniorities lists the seniorities in increasing order.
+var Seniorities = []string{SeniorityJunior, SeniorityIntermediate, SenioritySenior, SeniorityExpert}
+
+// Remote policies of a job ad.
+const (
+	RemoteOnsite = "onsite"
+	RemoteHybrid = "hybrid"
+	RemoteFull   = "remote"
+)
+
+// RemotePolicies lists the remote policies.
+var RemotePolicies = []string{RemoteOnsite, RemoteHybrid, RemoteFull}
+
+// JobMatch is how a resume fits a job ad: the skills of the job ad it has or lacks, and the
+// mission requirements it does not meet.
+type JobMatch struct {
+	ResumeID      int      `json:"resume_id"`
+	CandidateID   int      `json:"candidate_id,omitempty"`
+	FullName      string   `json:"full_name"`
+	MatchedSkills []string `json:"matched_skills"`
+	MissingSkills []string `json:"missing_skills"`
+	Mismatches    []string `json:"mismatches"`
 }
 
 type Experience struct {
@@ -24,25 +165,387 @@ type Experience struct {
 	JobTitle    string `json:"job_title"`
 	Description string `json:"description"`
 	Tools       string `json:"tools"`
+
+	// StartDate, EndDate and Current are parsed from Dates, Current meaning the experience is
+	// still going on and has no end date.
+	StartDate      *YearMonth `json:"start_date,omitempty" jsonschema:"-"`
+	EndDate        *YearMonth `json:"end_date,omitempty" jsonschema:"-"`
+	Current        bool       `json:"current,omitempty" jsonschema:"-"`
+	DurationMonths int        `json:"duration_months,omitempty" jsonschema:"-"`
+}
+
+// YearMonth is a month of a year, Month being zero when only the year is known. It is encoded
+// in JSON as "2006-01", or "2006" without month.
+type YearMonth struct {
+	Year  int
+	Month int
+}
+
+func (ym YearMonth) String() string {
+	if ym.Month == 0 {
+		return fmt.Sprintf("%04d", ym.Year)
+	}
+	return fmt.Sprintf("%04d-%02d", ym.Year, ym.Month)
+}
+
+func (ym YearMonth) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ym.String())
+}
+
+func (ym *YearMonth) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	year, month, hasMonth := strings.Cut(value, "-")
+	var err error
+	if ym.Year, err = strconv.Atoi(year); err != nil {
+		return fmt.Errorf("invalid year-month %q", value)
+	}
+	ym.Month = 0
+	if hasMonth {
+		if ym.Month, err = strconv.Atoi(month); err != nil || ym.Month < 1 || ym.Month > 12 {
+			return fmt.Errorf("invalid year-month %q", value)
+		}
+	}
+	return nil
+}
+
+// Skill categories. SkillLanguage is a programming language, spoken languages being Languages.
+const (
+	SkillLanguage  = "language"
+	SkillFramework = "framework"
+	SkillDatabase  = "database"
+	SkillCloud     = "cloud"
+	SkillDevOps    = "devops"
+	SkillTool      = "tool"
+	SkillMethod    = "method"
+	SkillOther     = "other"
+)
+
+// SkillCategories lists the skill categories.
+var SkillCategories = []string{SkillLanguage, SkillFramework, SkillDatabase, SkillCloud, SkillDevOps, SkillTool, SkillMethod, SkillOther}
+
+// Skill levels, from the lowest to the highest.
+const (
+	SkillBeginner     = "beginner"
+	SkillIntermediate = "intermediate"
+	SkillAdvanced     = "advanced"
+	SkillExpert       = "expert"
+)
+
+// SkillLevels lists the skill levels in increasing order.
+var SkillLevels = []string{SkillBeginner, SkillIntermediate, SkillAdvanced, SkillExpert}
+
+// Where the level of a skill comes from: stated by the resume, or inferred from the years the
+// skill was used.
+const (
+	LevelSelf     = "self"
+	LevelInferred = "inferred"
+)
+
+// Skill is a skill of a candidate. It is decoded from a JSON object, or from a plain string
+// holding its name as skills used to be.
+type Skill struct {
+	Name        string `json:"name"`
+	Category    string `json:"category" jsonschema:"description=One of language (programming languages only) framework database cloud devops tool method or other"`
+	Level       string `json:"level" jsonschema:"description=One of beginner intermediate advanced or expert when the resume states it or else an empty string"`
+	LevelSource string `json:"level_source,omitempty" jsonschema:"-"`
+	// Parents are the broader skills this one belongs to, such as Java for Spring Boot.
+	Parents   []string   `json:"parents,omitempty" jsonschema:"-"`
+	YearsUsed float64    `json:"years_used,omitempty" jsonschema:"-"`
+	LastUsed  *YearMonth `json:"last_used,omitempty" jsonschema:"-"`
+}
+
+func (s *Skill) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*s = Skill{Name: name}
+		return nil
+	}
+	type skill Skill
+	var value skill
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = Skill(value)
+	return nil
+}
+
+// SkillNames returns the names of skills.
+func SkillNames(skills []Skill) []string {
+	names := make([]string, len(skills))
+	for i, skill := range skills {
+		names[i] = skill.Name
+	}
+	return names
+}
+
+// SkillExperience is how long a candidate used a skill or tool, counting every month of the
+// experiences mentioning it once.
+type SkillExperience struct {
+	Skill  string  `json:"skill"`
+	Months int     `json:"months"`
+	Years  float64 `json:"years"`
+}
+
+type Education struct {
+	Degree string `json:"degree"`
+	School string `json:"school"`
+	Field  string `json:"field"`
+	Year   string `json:"year" jsonschema:"description=Year the degree was or will be obtained"`
+}
+
+// CEFR levels, from the lowest to the highest.
+const (
+	LevelA1 = "A1"
+	LevelA2 = "A2"
+	LevelB1 = "B1"
+	LevelB2 = "B2"
+	LevelC1 = "C1"
+	LevelC2 = "C2"
+)
+
+// CEFRLevels lists the CEFR levels in increasing order.
+var CEFRLevels = []string{LevelA1, LevelA2, LevelB1, LevelB2, LevelC1, LevelC2}
+
+// CEFRRank returns the rank of a CEFR level, from 1 for A1 to 6 for C2, or 0 for an unknown level.
+func CEFRRank(level string) int {
+	for i, l := range CEFRLevels {
+		if strings.EqualFold(l, strings.TrimSpace(level)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+type Language struct {
+	Name string `json:"name"`
+	// Proficiency is the level as the document states it, such as "fluent", "bilingue" or
+	// "TOEIC 850", and Level its CEFR equivalent.
+	Proficiency string `json:"proficiency"`
+	Level       string `json:"level" jsonschema:"description=CEFR level (A1 A2 B1 B2 C1 or C2) matching the proficiency or an empty string when it cannot be told"`
+	Native      bool   `json:"native" jsonschema:"description=Whether the language is a mother tongue"`
+}
+
+// Rate units.
+const (
+	RateDay   = "day"
+	RateHour  = "hour"
+	RateMonth = "month"
+)
+
+// RateUnits lists the rate units.
+var RateUnits = []string{RateDay, RateHour, RateMonth}
+
+// Billing modes of a consultant: self-employed, employed by the consulting firm, employed by a
+// portage company that bills on their behalf, or provided by another firm.
+const (
+	BillingFreelance      = "freelance"
+	BillingSalaried       = "salaried"
+	BillingPortage        = "portage"
+	BillingSubcontracting = "subcontracting"
+)
+
+// BillingModes lists the billing modes.
+var BillingModes = []string{BillingFreelance, BillingSalaried, BillingPortage, BillingSubcontracting}
+
+// Rate is an amount of money per day, hour or month, excluding taxes. A zero amount means no
+// rate is known.
+type Rate struct {
+	Amount   float64 `json:"amount" jsonschema:"description=Amount excluding taxes or 0 when no rate is stated"`
+	Currency string  `json:"currency" jsonschema:"description=ISO 4217 code of the currency such as EUR or USD"`
+	Unit     string  `json:"unit" jsonschema:"description=One of day or hour or month"`
+}
+
+// RateRange is a range of rates excluding taxes, such as the budget of a mission. A zero bound is
+// unknown.
+type RateRange struct {
+	Min      float64 `json:"min" jsonschema:"description=Lowest amount excluding taxes or 0 when not stated"`
+	Max      float64 `json:"max" jsonschema:"description=Highest amount excluding taxes or 0 when not stated"`
+	Currency string  `json:"currency" jsonschema:"description=ISO 4217 code of the currency such as EUR or USD"`
+	Unit     string  `json:"unit" jsonschema:"description=One of day or hour or month"`
+}
+
+func (r RateRange) String() string {
+	if r.Min == 0 && r.Max == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s %s/%s", strconv.FormatFloat(r.Min, 'f', -1, 64), strconv.FormatFloat(r.Max, 'f', -1, 64), r.Currency, r.Unit)
+}
+
+func (r Rate) String() string {
+	if r.Amount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %s/%s", strconv.FormatFloat(r.Amount, 'f', -1, 64), r.Currency, r.Unit)
+}
+
+// Candidate is a person, owning the resumes parsed for them along with contact details and
+// internal data that clients do not see.
+type Candidate struct {
+	ID        int      `json:"id"`
+	FullName  string   `json:"full_name"`
+	Emails    []string `json:"emails"`
+	Phones    []string `json:"phones"`
+	ResumeIDs []int    `json:"resume_ids"`
+
+	Rate *Rate `json:"rate,omitempty"`
+	// DailyRate is Rate per day in the base currency, when it can be converted.
+	DailyRate    *Rate  `json:"daily_rate,omitempty"`
+	BillingMode  string `json:"billing_mode"`
+	Availability string `json:"availability"`
+	Notes        string `json:"notes"`
+
+	// PossibleDuplicates lists the candidates that may be the same person, to be merged or
+	// dismissed.
+	PossibleDuplicates []DuplicateMatch `json:"possible_duplicates,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// DuplicateMatch is a candidate that may be the same person as another one, and why.
+type DuplicateMatch struct {
+	CandidateID int      `json:"candidate_id"`
+	Reasons     []string `json:"reasons"`
 }
 
 type CandidateResume struct {
-	ID               int          `json:"id"`
+	ID               int          `json:"id" jsonschema:"-"`
+	CandidateID      int          `json:"candidate_id" jsonschema:"-"`
 	FullName         string       `json:"full_name"`
+	Email            string       `json:"email"`
+	Phone            string       `json:"phone"`
 	Description      string       `json:"description"`
 	ShortDescription string       `json:"short_description"`
 	Experiences      []Experience `json:"experiences"`
 	Certifications   []string     `json:"certifications"`
-	Skills           []string     `json:"skills"`
+	Skills           []Skill      `json:"skills"`
+	Education        []Education  `json:"education"`
+	Languages        []Language   `json:"languages"`
 	Location         string       `json:"location"`
 	Availability     string       `json:"availability"`
-	Facturation      string       `json:"facturation"`
-	AverageDailyRate string       `json:"average_daily_rate"`
-	BillingMode      string       `json:"billing_mode"`
+	RemotePreference string       `json:"remote_preference" jsonschema:"description=One of onsite hybrid or remote when the resume states how the candidate wants to work or else an empty string"`
+	MissionDuration  string       `json:"mission_duration" jsonschema:"description=Shortest mission the candidate accepts as written such as 6 months minimum or an empty string"`
+	Rate             Rate         `json:"rate" jsonschema:"description=Rate the candidate asks for such as an average daily rate"`
+	BillingMode      string       `json:"billing_mode" jsonschema:"description=One of freelance salaried portage or subcontracting when the resume states it or else an empty string"`
+	// DailyRate is Rate per day in the base currency, when it can be converted.
+	DailyRate *Rate `json:"daily_rate,omitempty" jsonschema:"-"`
+	// AvailableASAP and AvailableFrom are parsed from Availability, MinMissionMonths from
+	// MissionDuration.
+	AvailableASAP    bool       `json:"available_asap,omitempty" jsonschema:"-"`
+	AvailableFrom    *YearMonth `json:"available_from,omitempty" jsonschema:"-"`
+	MinMissionMonths int        `json:"min_mission_months,omitempty" jsonschema:"-"`
+	// ExperienceMonths counts the months covered by dated experiences, overlapping experiences
+	// being counted once.
+	ExperienceMonths  int               `json:"experience_months" jsonschema:"-"`
+	YearsOfExperience float64           `json:"years_of_experience" jsonschema:"-"`
+	SkillExperience   []SkillExperience `json:"skill_experience,omitempty" jsonschema:"-"`
+	Provider          string            `json:"provider" jsonschema:"-"`
+	PromptVersion     string            `json:"prompt_version" jsonschema:"-"`
+
+	Source     *SourceDocument   `json:"source,omitempty" jsonschema:"-"`
+	Provenance []FieldProvenance `json:"provenance,omitempty" jsonschema:"-"`
+	Warnings   []string          `json:"warnings,omitempty" jsonschema:"-"`
+	// Suspicious content makes the entity need a review before its fields are trusted.
+	Suspicious  []SuspiciousContent `json:"suspicious,omitempty" jsonschema:"-"`
+	NeedsReview bool                `json:"needs_review" jsonschema:"-"`
 }
 
 type CandidateAdaptedResume struct {
-	ID     int             `json:"id"`
-	JobAd  JobAd           `json:"job_ad"`
-	Resume CandidateResume `json:"resume"`
+	ID              int                `json:"id"`
+	JobAdID         int                `json:"job_ad_id"`
+	CandidateID     int                `json:"candidate_id,omitempty"`
+	SourceResumeIDs []int              `json:"source_resume_ids"`
+	JobAd           JobAd              `json:"job_ad"`
+	Resume          CandidateResume    `json:"resume"`
+	Rationales      []SectionRationale `json:"rationales"`
+	Provider        string             `json:"provider"`
+	PromptVersion   string             `json:"prompt_version"`
+	// GroundingFindings lists the claims of Resume that the source resumes do not support.
+	GroundingFindings []GroundingFinding `json:"grounding_findings"`
+	NeedsReview       bool               `json:"needs_review"`
+	ReviewReasons     []string           `json:"review_reasons,omitempty"`
+}
+
+// GroundingFinding is a claim of an adapted resume that none of its source resumes supports.
+type GroundingFinding struct {
+	Field  string `json:"field"`
+	Kind   string `json:"kind"`
+	Value  string `json:"value"`
+	Action string `json:"action"`
+}
+
+// ResumeAdaptation is the structured output requested from providers when adapting resumes.
+type ResumeAdaptation struct {
+	Resume     CandidateResume    `json:"resume"`
+	Rationales []SectionRationale `json:"rationales"`
+}
+
+type SectionRationale struct {
+	Section         string   `json:"section" jsonschema:"description=Name of the resume section such as short_description or experiences"`
+	Rationale       string   `json:"rationale" jsonschema:"description=Why this section was rewritten or reordered this way"`
+	JobRequirements []string `json:"job_requirements" jsonschema:"description=Job ad requirements this section addresses"`
+}
+
+type ProviderHealth struct {
+	Provider            string    `json:"provider"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	OpenedAt            time.Time `json:"opened_at,omitzero"`
+	LastLatency         string    `json:"last_latency,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+type UsageRecord struct {
+	ID                int       `json:"id"`
+	CreatedAt         time.Time `json:"created_at"`
+	TenantID          string    `json:"tenant_id"`
+	UserID            string    `json:"user_id"`
+	Provider          string    `json:"provider"`
+	Model             string    `json:"model"`
+	Operation         string    `json:"operation"`
+	EntityType        string    `json:"entity_type,omitempty"`
+	EntityID          int       `json:"entity_id,omitempty"`
+	InputTokens       int64     `json:"input_tokens"`
+	CachedInputTokens int64     `json:"cached_input_tokens"`
+	OutputTokens      int64     `json:"output_tokens"`
+	UploadedBytes     int64     `json:"uploaded_bytes"`
+	CostUSD           float64   `json:"cost_usd"`
+}
+
+type UsageFilter struct {
+	From time.Time
+	To   time.Time
+}
+
+type UsageReportRow struct {
+	Key               string  `json:"key"`
+	Calls             int     `json:"calls"`
+	InputTokens       int64   `json:"input_tokens"`
+	CachedInputTokens int64   `json:"cached_input_tokens"`
+	OutputTokens      int64   `json:"output_tokens"`
+	UploadedBytes     int64   `json:"uploaded_bytes"`
+	CostUSD           float64 `json:"cost_usd"`
+}
+
+type BudgetStatus struct {
+	Name         string    `json:"name"`
+	Scope        string    `json:"scope"`
+	Key          string    `json:"key"`
+	SoftLimitUSD float64   `json:"soft_limit_usd"`
+	HardLimitUSD float64   `json:"hard_limit_usd"`
+	SpentUSD     float64   `json:"spent_usd"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	SoftExceeded bool      `json:"soft_exceeded"`
+	HardExceeded bool      `json:"hard_exceeded"`
+}
+
+type PromptInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
 }
@@ -0,0 +1,175 @@
+// Package period parses the free-form date ranges of resume experiences, written in French or
+// English, such as "janv. 2019 - aujourd'hui", "03/2017 – 12/2018" or "Since March 2020".
+package period
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// minYear and maxYearAhead bound the years accepted as dates.
+const (
+	minYear      = 1950
+	maxYearAhead = 1
+)
+
+// Period is a parsed date range. End is nil when the period is still going on, and equals Start
+// when a single date was given.
+type Period struct {
+	Start   model.YearMonth
+	End     *model.YearMonth
+	Current bool
+}
+
+var months = map[string]int{
+	"jan": 1, "janv": 1, "janvier": 1, "january": 1,
+	"fev": 2, "fevr": 2, "fevrier": 2, "feb": 2, "february": 2,
+	"mar": 3, "mars": 3, "march": 3,
+	"avr": 4, "avril": 4, "apr": 4, "april": 4,
+	"mai": 5, "may": 5,
+	"juin": 6, "jun": 6, "june": 6,
+	"juil": 7, "juillet": 7, "jul": 7, "july": 7,
+	"aou": 8, "aout": 8, "aug": 8, "august": 8,
+	"sep": 9, "sept": 9, "septembre": 9, "september": 9,
+	"oct": 10, "octobre": 10, "october": 10,
+	"nov": 11, "novembre": 11, "november": 11,
+	"dec": 12, "decembre": 12, "december": 12,
+}
+
+// presentWords end a period that is still going on. They are compared without accents.
+var presentWords = regexp.MustCompile(`\b(?:present|aujourd ?hui|a ce jour|actuel(?:lement)?|en cours|now|current(?:ly)?|today|ongoing|to date)\b`)
+
+// sinceWords start a period that is still going on when no end date follows.
+var sinceWords = regexp.MustCompile(`\b(?:depuis|since)\b`)
+
+// datePattern matches, in text without accents: a month name and a year, a numeric month and
+// year in either order, or a year alone. A year comes before a month only in the compact
+// 2019-03 form, so that "2017 - 2 ans" is not read as February 2017.
+var datePattern = regexp.MustCompile(`\b(?:([a-z]{3,9})\.?\s+(\d{4})|(\d{1,2})\s*[/.-]\s*(\d{4})|(\d{4})[/.-](\d{2})\b|(\d{4}))\b`)
+
+// folding removes the accents of French dates, and turns apostrophes into spaces.
+var folding = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u",
+	"'", " ", "’", " ",
+)
+
+// Parse reads the period of text relative to now. It reports false when text holds no plausible
+// date.
+func Parse(text string, now time.Time) (Period, bool) {
+	normalized := normalize(text)
+	var dates []model.YearMonth
+	var ends []int
+	for _, match := range datePattern.FindAllStringSubmatchIndex(normalized, -1) {
+		date, ok := parseDate(normalized, match, now)
+		if !ok {
+			continue
+		}
+		dates = append(dates, date)
+		ends = append(ends, match[1])
+		if len(dates) == 2 {
+			break
+		}
+	}
+	if len(dates) == 0 {
+		return Period{}, false
+	}
+
+	p := Period{Start: dates[0]}
+	switch {
+	case len(dates) == 2:
+		p.End = &dates[1]
+		if monthIndex(*p.End, true) < monthIndex(p.Start, false) {
+			p.Start, *p.End = *p.End, p.Start
+		}
+	case presentWords.MatchString(normalized[ends[0]:]) || sinceWords.MatchString(normalized):
+		p.Current = true
+	default:
+		end := dates[0]
+		p.End = &end
+	}
+	return p, true
+}
+
+// Months returns the number of months a period covers, counting its first and last months. Years
+// without month cover from January to December.
+func (p Period) Months(now time.Time) int {
+	first, last := p.Bounds(now)
+	return max(last-first+1, 0)
+}
+
+// Bounds returns the indexes (year*12 + month-1) of the first and last months of the period, a
+// current period ending in the month of now.
+func (p Period) Bounds(now time.Time) (int, int) {
+	end := model.YearMonth{Year: now.Year(), Month: int(now.Month())}
+	if p.End != nil {
+		end = *p.End
+	}
+	return monthIndex(p.Start, false), monthIndex(end, true)
+}
+
+// monthIndex returns year*12 + month-1, a year without month standing for its first month when
+// starting a period and for its last month when ending one.
+func monthIndex(ym model.YearMonth, end bool) int {
+	month := ym.Month
+	if month == 0 {
+		month = 1
+		if end {
+			month = 12
+		}
+	}
+	return ym.Year*12 + month - 1
+}
+
+func parseDate(text string, match []int, now time.Time) (model.YearMonth, bool) {
+	group := func(i int) string {
+		if match[2*i] < 0 {
+			return ""
+		}
+		return text[match[2*i]:match[2*i+1]]
+	}
+	var date model.YearMonth
+	switch {
+	case group(1) != "":
+		month, ok := months[group(1)]
+		if !ok {
+			// A word that is not a month, such as "since", may precede a year.
+			return parseYear(group(2), now)
+		}
+		date.Month = month
+		date.Year, _ = strconv.Atoi(group(2))
+	case group(3) != "":
+		date.Month, _ = strconv.Atoi(group(3))
+		date.Year, _ = strconv.Atoi(group(4))
+	case group(5) != "":
+		date.Year, _ = strconv.Atoi(group(5))
+		date.Month, _ = strconv.Atoi(group(6))
+	default:
+		return parseYear(group(7), now)
+	}
+	if date.Month < 1 || date.Month > 12 || !plausibleYear(date.Year, now) {
+		return model.YearMonth{}, false
+	}
+	return date, true
+}
+
+func parseYear(value string, now time.Time) (model.YearMonth, bool) {
+	year, err := strconv.Atoi(value)
+	if err != nil || !plausibleYear(year, now) {
+		return model.YearMonth{}, false
+	}
+	return model.YearMonth{Year: year}, true
+}
+
+func plausibleYear(year int, now time.Time) bool {
+	return year >= minYear && year <= now.Year()+maxYearAhead
+}
+
+// normalize lowercases and folds text, and collapses its spaces.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(folding.Replace(strings.ToLower(text))), " ")
+}
@@ -0,0 +1,67 @@
+package period
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	ym := func(year, month int) *model.YearMonth {
+		return &model.YearMonth{Year: year, Month: month}
+	}
+	tests := []struct {
+		text    string
+		start   model.YearMonth
+		end     *model.YearMonth
+		current bool
+		months  int
+	}{
+		{"janv. 2019 - aujourd'hui", *ym(2019, 1), nil, true, 66},
+		{"sept. 2020 – présent", *ym(2020, 9), nil, true, 46},
+		{"03/2017 – 12/2018", *ym(2017, 3), ym(2018, 12), false, 22},
+		{"01.2019-12.2021", *ym(2019, 1), ym(2021, 12), false, 36},
+		{"2019-03 / 2020-11", *ym(2019, 3), ym(2020, 11), false, 21},
+		{"Since March 2020", *ym(2020, 3), nil, true, 52},
+		{"Depuis 2021", *ym(2021, 0), nil, true, 42},
+		{"Juin 2018", *ym(2018, 6), ym(2018, 6), false, 1},
+		{"2015 - 2018", *ym(2015, 0), ym(2018, 0), false, 48},
+		{"2017", *ym(2017, 0), ym(2017, 0), false, 12},
+		{"2017 - 2 ans", *ym(2017, 0), ym(2017, 0), false, 12},
+		{"2018 - 2015", *ym(2015, 0), ym(2018, 0), false, 48},
+		{"12/2020 - 01/2019", *ym(2019, 1), ym(2020, 12), false, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			p, ok := Parse(tt.text, now)
+			if !ok {
+				t.Fatalf("Parse(%q) reported no date", tt.text)
+			}
+			if p.Start != tt.start || p.Current != tt.current || !sameMonth(p.End, tt.end) {
+				t.Errorf("Parse(%q) = %v - %v (current %t), want %v - %v (current %t)",
+					tt.text, p.Start, p.End, p.Current, tt.start, tt.end, tt.current)
+			}
+			if months := p.Months(now); months != tt.months {
+				t.Errorf("Parse(%q).Months() = %d, want %d", tt.text, months, tt.months)
+			}
+		})
+	}
+}
+
+func TestParseWithoutDate(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{"", "Mission de 6 mois", "1900 - 1910", "2030"} {
+		if p, ok := Parse(text, now); ok {
+			t.Errorf("Parse(%q) = %v, want no date", text, p)
+		}
+	}
+}
+
+func sameMonth(a, b *model.YearMonth) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
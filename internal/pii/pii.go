@@ -0,0 +1,228 @@
+// Package pii replaces personal data in texts with placeholders, and restores them in the texts
+// derived from the redacted ones.
+package pii
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Kinds of personal data, used in placeholders such as [EMAIL_1].
+const (
+	KindName      = "NAME"
+	KindEmail     = "EMAIL"
+	KindPhone     = "PHONE"
+	KindAddress   = "ADDRESS"
+	KindBirthDate = "BIRTHDATE"
+	KindProfile   = "PROFILE"
+)
+
+// maxNameLines is how many lines at the top of a document are searched for the candidate name.
+const maxNameLines = 5
+
+var (
+	emailPattern   = regexp.MustCompile(`[\p{L}0-9._%+-]+@[\p{L}0-9.-]+\.[A-Za-z]{2,}`)
+	profilePattern = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z]{2,3}\.)?(?:linkedin\.com/in|github\.com|gitlab\.com|twitter\.com|x\.com)/[\w%.-]+/?`)
+	// phonePattern matches international numbers, and national ones written as ten digits
+	// grouped by two with a single kind of separator, so that dates such as 01.2019 are left alone.
+	phonePattern = regexp.MustCompile(`\+\d{1,3}[\s.-]?(?:\(0\)[\s.-]?)?\d{1,3}(?:[\s.-]?\d{2,4}){2,5}\b|` +
+		`\b0\d(?:\d{8}|(?: \d{2}){4}|(?:\.\d{2}){4}|(?:-\d{2}){4})\b`)
+	// birthDatePattern captures the date following a birth label, in numeric or written form.
+	birthDatePattern = regexp.MustCompile(`(?i)(?:n[ée]e?\s+le|date\s+de\s+naissance|born(?:\s+on)?|date\s+of\s+birth|d\.?o\.?b\.?)\s*:?\s*` +
+		`(\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4}|\d{1,2}(?:er)?\s+\p{L}+\.?\s+\d{4}|\p{L}+\s+\d{1,2},?\s+\d{4}|\d{4}-\d{2}-\d{2})`)
+	addressPattern = regexp.MustCompile(`(?i)\b\d{1,4}(?:\s?(?:bis|ter))?,?\s+` +
+		`(?:rue|avenue|av\.|boulevard|bd|chemin|all[ée]e|place|impasse|quai|route|cours|square|street|st\.|road|rd\.|lane|drive|way)\b` +
+		`[^\n,;]*(?:,?\s*\d{5}\s+[\p{L}' -]*\p{L})?`)
+	nameLabelPattern = regexp.MustCompile(`(?im)^\s*(?:nom(?:\s+complet)?|pr[ée]nom\s+nom|full\s+name|name)\s*:\s*(.+?)\s*$`)
+	placeholder      = regexp.MustCompile(`\[(?:NAME|EMAIL|PHONE|ADDRESS|BIRTHDATE|PROFILE)_\d+\]`)
+	stringType       = reflect.TypeOf("")
+)
+
+// resumeWords are the title words that a line naming the candidate does not contain.
+var resumeWords = map[string]bool{
+	"cv": true, "curriculum": true, "vitae": true, "resume": true, "résumé": true,
+	"profil": true, "profile": true, "contact": true, "expériences": true, "experience": true,
+}
+
+// Pseudonymizer replaces each distinct personal value with the same placeholder every time it is
+// seen, and keeps the originals to restore them. It is meant for a single document or call.
+type Pseudonymizer struct {
+	values   map[string]string
+	keys     map[string]string
+	counters map[string]int
+	names    []*regexp.Regexp
+}
+
+func New() *Pseudonymizer {
+	return &Pseudonymizer{
+		values:   make(map[string]string),
+		keys:     make(map[string]string),
+		counters: make(map[string]int),
+	}
+}
+
+// AddName registers a person name to redact wherever it appears, along with its last word when
+// the name has several, as surnames are often used alone.
+func (p *Pseudonymizer) AddName(name string) {
+	words := strings.Fields(name)
+	if len(words) == 0 || placeholder.MatchString(name) {
+		return
+	}
+	p.addNamePattern(words)
+	if last := words[len(words)-1]; len(words) > 1 && len([]rune(last)) > 2 {
+		p.addNamePattern([]string{last})
+	}
+}
+
+func (p *Pseudonymizer) addNamePattern(words []string) {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	p.names = append(p.names, regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(`+strings.Join(quoted, `[\s-]+`)+`)(?:$|[^\p{L}\p{N}])`))
+}
+
+// DetectNames registers the candidate name of a document: the value of a "Name:" line, or else
+// the first line at the top of the document that looks like a person name.
+func (p *Pseudonymizer) DetectNames(text string) {
+	if match := nameLabelPattern.FindStringSubmatch(text); match != nil {
+		p.AddName(match[1])
+		return
+	}
+	seen := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if looksLikeName(line) {
+			p.AddName(line)
+			return
+		}
+		if seen++; seen == maxNameLines {
+			return
+		}
+	}
+}
+
+// looksLikeName reports whether line is made of two to four capitalized words of letters.
+func looksLikeName(line string) bool {
+	words := strings.Fields(line)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+	for _, word := range words {
+		if resumeWords[strings.ToLower(word)] {
+			return false
+		}
+		for i, r := range word {
+			switch {
+			case i == 0 && !unicode.IsUpper(r):
+				return false
+			case !unicode.IsLetter(r) && r != '-' && r != '\'' && r != '.':
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Redact replaces the personal data of text with placeholders.
+func (p *Pseudonymizer) Redact(text string) string {
+	text = p.replaceAll(text, emailPattern, KindEmail)
+	text = p.replaceAll(text, profilePattern, KindProfile)
+	text = p.replaceGroup(text, birthDatePattern, KindBirthDate)
+	text = p.replaceAll(text, addressPattern, KindAddress)
+	text = p.replaceAll(text, phonePattern, KindPhone)
+	for _, name := range p.names {
+		text = p.replaceGroup(text, name, KindName)
+	}
+	return text
+}
+
+// Restore replaces the placeholders of text with the values they stand for.
+func (p *Pseudonymizer) Restore(text string) string {
+	if !strings.Contains(text, "[") {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(key string) string {
+		if value, ok := p.values[key]; ok {
+			return value
+		}
+		return key
+	})
+}
+
+// RedactValue redacts every string reachable from ptr, which must point to a value that shares
+// no memory with data that must be kept intact.
+func (p *Pseudonymizer) RedactValue(ptr any) {
+	transformStrings(reflect.ValueOf(ptr), p.Redact)
+}
+
+// RestoreValue restores the placeholders of every string reachable from ptr.
+func (p *Pseudonymizer) RestoreValue(ptr any) {
+	transformStrings(reflect.ValueOf(ptr), p.Restore)
+}
+
+func (p *Pseudonymizer) replaceAll(text string, pattern *regexp.Regexp, kind string) string {
+	return pattern.ReplaceAllStringFunc(text, func(value string) string {
+		return p.placeholder(kind, value)
+	})
+}
+
+// replaceGroup replaces the first capture group of each match of pattern.
+func (p *Pseudonymizer) replaceGroup(text string, pattern *regexp.Regexp, kind string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		if start < 0 {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(p.placeholder(kind, text[start:end]))
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// placeholder returns the placeholder of value, the same for values that only differ by case
+// or spacing.
+func (p *Pseudonymizer) placeholder(kind, value string) string {
+	key := kind + "|" + strings.ToLower(strings.Join(strings.Fields(value), " "))
+	if existing, ok := p.keys[key]; ok {
+		return existing
+	}
+	p.counters[kind]++
+	name := fmt.Sprintf("[%s_%d]", kind, p.counters[kind])
+	p.keys[key] = name
+	p.values[name] = value
+	return name
+}
+
+func transformStrings(v reflect.Value, transform func(string) string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			transformStrings(v.Elem(), transform)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				transformStrings(v.Field(i), transform)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			transformStrings(v.Index(i), transform)
+		}
+	case reflect.String:
+		if v.CanSet() && v.Type() == stringType {
+			v.SetString(transform(v.String()))
+		}
+	}
+}
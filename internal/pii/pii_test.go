@@ -0,0 +1,36 @@
+package pii
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"dotted date range", "Experience 01.2019-12.2021", "Experience 01.2019-12.2021"},
+		{"slashed date range", "Expérience 03/2017 - 12/2018", "Expérience 03/2017 - 12/2018"},
+		{"dashed dates", "Mission 2019-01-15 to 2021-12-31", "Mission 2019-01-15 to 2021-12-31"},
+		{"french phone with spaces", "Tél : 06 12 34 56 78", "Tél : [PHONE_1]"},
+		{"french phone with dots", "Tel 06.12.34.56.78", "Tel [PHONE_1]"},
+		{"french phone without separators", "Tel 0612345678", "Tel [PHONE_1]"},
+		{"international phone", "Phone: +33 6 12 34 56 78", "Phone: [PHONE_1]"},
+		{"international phone with trunk prefix", "Phone: +33 (0)6 12 34 56 78", "Phone: [PHONE_1]"},
+		{"british phone", "Phone: +44 20 7946 0958", "Phone: [PHONE_1]"},
+		{"mixed separators", "Ref 06.12-34 56.78", "Ref 06.12-34 56.78"},
+		{"french address", "Adresse : 12 rue de la Paix, 75002 Paris", "Adresse : [ADDRESS_1]"},
+		{"address with bis", "Domicile : 5 bis avenue Victor Hugo", "Domicile : [ADDRESS_1]"},
+		{"same phone spelled twice", "06 12 34 56 78 or 06.12.34.56.78", "[PHONE_1] or [PHONE_2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			if got := p.Redact(tt.text); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if got := p.Restore(p.Redact(tt.text)); got != tt.text {
+				t.Errorf("Restore(Redact(%q)) = %q", tt.text, got)
+			}
+		})
+	}
+}
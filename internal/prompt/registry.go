@@ -0,0 +1,171 @@
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+//go:embed templates/*.tmpl
+var embedded embed.FS
+
+// DefaultLocale is used when a prompt has no variant for the requested locale.
+const DefaultLocale = "en"
+
+// versionPattern matches the header every template starts with: {{- /* version: 3 */ -}}
+var versionPattern = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*([\w.-]+)\s*\*/\s*-?\}\}`)
+
+var funcs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// Prompt is a named, versioned and localized template.
+type Prompt struct {
+	Name    string
+	Locale  string
+	Version string
+	Source  string
+	tmpl    *template.Template
+}
+
+// ID identifies the exact prompt used to produce an entity, e.g. "parse_resume.fr@2".
+func (p *Prompt) ID() string {
+	return fmt.Sprintf("%s.%s@%s", p.Name, p.Locale, p.Version)
+}
+
+// Registry holds the active prompts, keyed by name and locale.
+type Registry struct {
+	prompts map[string]map[string]*Prompt
+}
+
+// Load reads the embedded prompts, then the *.tmpl files of overrideDir if it is not empty.
+// Files are named <name>.<locale>.tmpl and those of overrideDir replace the embedded ones.
+func Load(overrideDir string) (*Registry, error) {
+	r := &Registry{prompts: make(map[string]map[string]*Prompt)}
+	templates, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadFS(templates, "embedded"); err != nil {
+		return nil, err
+	}
+	if overrideDir != "" {
+		if err := r.loadFS(os.DirFS(overrideDir), overrideDir); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) loadFS(fsys fs.FS, source string) error {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to list prompts in %s: %w", source, err)
+	}
+	for _, file := range files {
+		p, err := parsePrompt(fsys, file, source)
+		if err != nil {
+			return err
+		}
+		if r.prompts[p.Name] == nil {
+			r.prompts[p.Name] = make(map[string]*Prompt)
+		}
+		r.prompts[p.Name][p.Locale] = p
+	}
+	return nil
+}
+
+func parsePrompt(fsys fs.FS, file, source string) (*Prompt, error) {
+	name, locale, ok := strings.Cut(strings.TrimSuffix(file, ".tmpl"), ".")
+	if !ok || name == "" || locale == "" {
+		return nil, fmt.Errorf("prompt file '%s' must be named <name>.<locale>.tmpl", path.Join(source, file))
+	}
+	raw, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt '%s': %w", path.Join(source, file), err)
+	}
+	match := versionPattern.FindSubmatch(raw)
+	if match == nil {
+		return nil, fmt.Errorf("prompt '%s' must start with a {{/* version: N */}} header", path.Join(source, file))
+	}
+	tmpl, err := template.New(file).Funcs(funcs).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt '%s': %w", path.Join(source, file), err)
+	}
+	return &Prompt{
+		Name:    name,
+		Locale:  locale,
+		Version: string(match[1]),
+		Source:  source,
+		tmpl:    tmpl,
+	}, nil
+}
+
+// Get returns the prompt for locale, falling back to DefaultLocale.
+func (r *Registry) Get(name, locale string) (*Prompt, error) {
+	variants, ok := r.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("prompt '%s' does not exist", name)
+	}
+	if p, ok := variants[locale]; ok {
+		return p, nil
+	}
+	if p, ok := variants[DefaultLocale]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("prompt '%s' has no '%s' or '%s' variant", name, locale, DefaultLocale)
+}
+
+// Render executes the prompt for locale with data and returns the text along with the prompt used.
+func (r *Registry) Render(name, locale string, data any) (string, *Prompt, error) {
+	p, err := r.Get(name, locale)
+	if err != nil {
+		return "", nil, err
+	}
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, data); err != nil {
+		return "", nil, fmt.Errorf("failed to render prompt %s: %w", p.ID(), err)
+	}
+	return buf.String(), p, nil
+}
+
+// Prompts lists every active prompt, sorted by name and locale.
+func (r *Registry) Prompts() []model.PromptInfo {
+	var infos []model.PromptInfo
+	for _, variants := range r.prompts {
+		for _, p := range variants {
+			infos = append(infos, model.PromptInfo{
+				ID:      p.ID(),
+				Name:    p.Name,
+				Locale:  p.Locale,
+				Version: p.Version,
+				Source:  p.Source,
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// Require checks that every given prompt has at least a DefaultLocale variant.
+func (r *Registry) Require(names ...string) error {
+	var errs []error
+	for _, name := range names {
+		if _, ok := r.prompts[name][DefaultLocale]; !ok {
+			errs = append(errs, fmt.Errorf("prompt '%s' has no '%s' variant", name, DefaultLocale))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/service"
 )
 
 // MemoryResumeRepo is an in-memory implementation of the ResumeRepository interface.
@@ -17,10 +18,12 @@ type MemoryResumeRepo struct {
 	resumes        map[int]model.CandidateResume
 	jobAds         map[int]model.JobAd
 	adaptedResumes map[int]model.CandidateAdaptedResume
+	candidates     map[int]model.Candidate
 
 	nextResumeID        int
 	nextJobAdID         int
 	nextAdaptedResumeID int
+	nextCandidateID     int
 }
 
 // NewMemoryResumeRepo creates and initializes a new in-memory repository.
@@ -29,10 +32,12 @@ func NewMemoryResumeRepo() *MemoryResumeRepo {
 		resumes:        make(map[int]model.CandidateResume),
 		jobAds:         make(map[int]model.JobAd),
 		adaptedResumes: make(map[int]model.CandidateAdaptedResume),
+		candidates:     make(map[int]model.Candidate),
 
 		nextResumeID:        1,
 		nextJobAdID:         1,
 		nextAdaptedResumeID: 1,
+		nextCandidateID:     1,
 	}
 }
 
@@ -55,7 +60,7 @@ func (r *MemoryResumeRepo) GetAdaptedResume(ctx context.Context, adaptedResumeID
 
 	resume, ok := r.adaptedResumes[adaptedResumeID]
 	if !ok {
-		return model.CandidateAdaptedResume{}, fmt.Errorf("adapted resume with ID %d not found", adaptedResumeID)
+		return model.CandidateAdaptedResume{}, fmt.Errorf("adapted resume with ID %d %w", adaptedResumeID, service.ErrNotFound)
 	}
 	return resume, nil
 }
@@ -80,7 +85,7 @@ func (r *MemoryResumeRepo) UpdateAdaptedResume(ctx context.Context, adaptedResum
 	defer r.mu.Unlock()
 
 	if _, ok := r.adaptedResumes[adaptedResume.ID]; !ok {
-		return model.CandidateAdaptedResume{}, fmt.Errorf("adapted resume with ID %d not found for update", adaptedResume.ID)
+		return model.CandidateAdaptedResume{}, fmt.Errorf("adapted resume with ID %d %w for update", adaptedResume.ID, service.ErrNotFound)
 	}
 	r.adaptedResumes[adaptedResume.ID] = adaptedResume
 	return adaptedResume, nil
@@ -91,7 +96,7 @@ func (r *MemoryResumeRepo) DeleteAdaptedResume(ctx context.Context, adaptedResum
 	defer r.mu.Unlock()
 
 	if _, ok := r.adaptedResumes[adaptedResumeID]; !ok {
-		return fmt.Errorf("adapted resume with ID %d not found for deletion", adaptedResumeID)
+		return fmt.Errorf("adapted resume with ID %d %w for deletion", adaptedResumeID, service.ErrNotFound)
 	}
 	delete(r.adaptedResumes, adaptedResumeID)
 	return nil
@@ -116,7 +121,7 @@ func (r *MemoryResumeRepo) GetResume(ctx context.Context, resumeID int) (model.C
 
 	resume, ok := r.resumes[resumeID]
 	if !ok {
-		return model.CandidateResume{}, fmt.Errorf("resume with ID %d not found", resumeID)
+		return model.CandidateResume{}, fmt.Errorf("resume with ID %d %w", resumeID, service.ErrNotFound)
 	}
 	return resume, nil
 }
@@ -140,7 +145,7 @@ func (r *MemoryResumeRepo) UpdateResume(ctx context.Context, resume model.Candid
 	defer r.mu.Unlock()
 
 	if _, ok := r.resumes[resume.ID]; !ok {
-		return model.CandidateResume{}, fmt.Errorf("resume with ID %d not found for update", resume.ID)
+		return model.CandidateResume{}, fmt.Errorf("resume with ID %d %w for update", resume.ID, service.ErrNotFound)
 	}
 	r.resumes[resume.ID] = resume
 	return resume, nil
@@ -151,7 +156,7 @@ func (r *MemoryResumeRepo) DeleteResume(ctx context.Context, resumeID int) error
 	defer r.mu.Unlock()
 
 	if _, ok := r.resumes[resumeID]; !ok {
-		return fmt.Errorf("resume with ID %d not found for deletion", resumeID)
+		return fmt.Errorf("resume with ID %d %w for deletion", resumeID, service.ErrNotFound)
 	}
 	delete(r.resumes, resumeID)
 	return nil
@@ -176,7 +181,7 @@ func (r *MemoryResumeRepo) GetJobAd(ctx context.Context, jobAdID int) (model.Job
 
 	jobAd, ok := r.jobAds[jobAdID]
 	if !ok {
-		return model.JobAd{}, fmt.Errorf("job ad with ID %d not found", jobAdID)
+		return model.JobAd{}, fmt.Errorf("job ad with ID %d %w", jobAdID, service.ErrNotFound)
 	}
 	return jobAd, nil
 }
@@ -200,7 +205,7 @@ func (r *MemoryResumeRepo) UpdateJobAd(ctx context.Context, jobAd model.JobAd) (
 	defer r.mu.Unlock()
 
 	if _, ok := r.jobAds[jobAd.ID]; !ok {
-		return model.JobAd{}, fmt.Errorf("job ad with ID %d not found for update", jobAd.ID)
+		return model.JobAd{}, fmt.Errorf("job ad with ID %d %w for update", jobAd.ID, service.ErrNotFound)
 	}
 	r.jobAds[jobAd.ID] = jobAd
 	return jobAd, nil
@@ -211,8 +216,68 @@ func (r *MemoryResumeRepo) DeleteJobAd(ctx context.Context, jobAdID int) error {
 	defer r.mu.Unlock()
 
 	if _, ok := r.jobAds[jobAdID]; !ok {
-		return fmt.Errorf("job ad with ID %d not found for deletion", jobAdID)
+		return fmt.Errorf("job ad with ID %d %w for deletion", jobAdID, service.ErrNotFound)
 	}
 	delete(r.jobAds, jobAdID)
 	return nil
 }
+
+// --- Candidate Methods ---
+
+func (r *MemoryResumeRepo) SaveCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	candidate.ID = r.nextCandidateID
+	r.candidates[candidate.ID] = candidate
+	r.nextCandidateID++
+
+	return candidate, nil
+}
+
+func (r *MemoryResumeRepo) GetCandidate(ctx context.Context, candidateID int) (model.Candidate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candidate, ok := r.candidates[candidateID]
+	if !ok {
+		return model.Candidate{}, fmt.Errorf("candidate with ID %d %w", candidateID, service.ErrNotFound)
+	}
+	return candidate, nil
+}
+
+func (r *MemoryResumeRepo) ListCandidates(ctx context.Context) ([]model.Candidate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	candidates := make([]model.Candidate, 0, len(r.candidates))
+	for _, candidate := range r.candidates {
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+	return candidates, nil
+}
+
+func (r *MemoryResumeRepo) UpdateCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.candidates[candidate.ID]; !ok {
+		return model.Candidate{}, fmt.Errorf("candidate with ID %d %w for update", candidate.ID, service.ErrNotFound)
+	}
+	r.candidates[candidate.ID] = candidate
+	return candidate, nil
+}
+
+func (r *MemoryResumeRepo) DeleteCandidate(ctx context.Context, candidateID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.candidates[candidateID]; !ok {
+		return fmt.Errorf("candidate with ID %d %w for deletion", candidateID, service.ErrNotFound)
+	}
+	delete(r.candidates, candidateID)
+	return nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// MemoryUsageRepo is an in-memory implementation of the UsageRepository interface.
+// Records are kept in insertion order and it is safe for concurrent use.
+type MemoryUsageRepo struct {
+	mu sync.RWMutex
+
+	records []model.UsageRecord
+	nextID  int
+}
+
+// NewMemoryUsageRepo creates and initializes a new in-memory usage repository.
+func NewMemoryUsageRepo() *MemoryUsageRepo {
+	return &MemoryUsageRepo{
+		nextID: 1,
+	}
+}
+
+func (r *MemoryUsageRepo) SaveUsage(ctx context.Context, record model.UsageRecord) (model.UsageRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.ID = r.nextID
+	r.records = append(r.records, record)
+	r.nextID++
+
+	return record, nil
+}
+
+func (r *MemoryUsageRepo) ListUsage(ctx context.Context, filter model.UsageFilter) ([]model.UsageRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]model.UsageRecord, 0, len(r.records))
+	for _, record := range r.records {
+		if !filter.From.IsZero() && record.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !record.CreatedAt.Before(filter.To) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import "context"
+
+// Actor identifies the tenant and user on whose behalf a request is made. Admin actors may also
+// read usage and provider health, and change budgets and the taxonomy.
+type Actor struct {
+	TenantID string
+	UserID   string
+	Admin    bool
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the given actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, or the zero Actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// ErrBudgetExceeded is matched by every *BudgetExceededError.
+var ErrBudgetExceeded = errors.New("llm budget exceeded")
+
+type BudgetScope string
+
+const (
+	BudgetScopeTenant   BudgetScope = "tenant"
+	BudgetScopeUser     BudgetScope = "user"
+	BudgetScopeProvider BudgetScope = "provider"
+)
+
+// BudgetAnyKey makes a budget apply separately to every tenant, user or provider of its scope.
+const BudgetAnyKey = "*"
+
+// Budget is a monthly spending limit in USD. Reaching the soft limit logs a warning, reaching the
+// hard limit refuses new LLM calls until the next month. A zero limit is not enforced.
+type Budget struct {
+	Name         string
+	Scope        BudgetScope
+	Key          string
+	SoftLimitUSD float64
+	HardLimitUSD float64
+}
+
+func (b Budget) appliesTo(key string) bool {
+	return b.Key == BudgetAnyKey || b.Key == key
+}
+
+type BudgetExceededError struct {
+	Budget   string
+	Scope    BudgetScope
+	Key      string
+	SpentUSD float64
+	LimitUSD float64
+	ResetsAt time.Time
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("llm budget '%s' exceeded for %s '%s': spent %.2f USD of %.2f USD, resets at %s",
+		e.Budget, e.Scope, e.Key, e.SpentUSD, e.LimitUSD, e.ResetsAt.Format(time.RFC3339))
+}
+
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// budgetStore holds the configured budgets, whose limits can be changed at runtime by an admin.
+type budgetStore struct {
+	mu      sync.RWMutex
+	budgets map[string]Budget
+}
+
+// WithBudgets enforces the given monthly budgets. It requires usage tracking to be enabled.
+func WithBudgets(budgets []Budget) Option {
+	return func(s *SynthesizerService) {
+		s.budgets.budgets = make(map[string]Budget, len(budgets))
+		for _, budget := range budgets {
+			s.budgets.budgets[budget.Name] = budget
+		}
+	}
+}
+
+func (bs *budgetStore) list(scopes ...BudgetScope) []Budget {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	budgets := make([]Budget, 0, len(bs.budgets))
+	for _, budget := range bs.budgets {
+		if len(scopes) == 0 || slices.Contains(scopes, budget.Scope) {
+			budgets = append(budgets, budget)
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].Name < budgets[j].Name
+	})
+	return budgets
+}
+
+// budgetPeriod returns the calendar month, in UTC, containing now.
+func budgetPeriod(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func scopeKey(scope BudgetScope, record model.UsageRecord) string {
+	switch scope {
+	case BudgetScopeTenant:
+		return record.TenantID
+	case BudgetScopeUser:
+		return record.UserID
+	default:
+		return record.Provider
+	}
+}
+
+// spending sums the cost of the current period by key of the given scope.
+func (s *SynthesizerService) spending(ctx context.Context, scope BudgetScope) (map[string]float64, error) {
+	start, end := budgetPeriod(time.Now())
+	records, err := s.usageRepository.ListUsage(ctx, model.UsageFilter{From: start, To: end})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage for budgets: %w", err)
+	}
+	spent := make(map[string]float64)
+	for _, record := range records {
+		spent[scopeKey(scope, record)] += record.CostUSD
+	}
+	return spent, nil
+}
+
+// checkBudgets returns a *BudgetExceededError if a hard limit of the given scope is reached for
+// key, and logs a warning for every soft limit reached.
+func (s *SynthesizerService) checkBudgets(ctx context.Context, scope BudgetScope, key string) error {
+	budgets := s.budgets.list(scope)
+	if len(budgets) == 0 || s.usageRepository == nil {
+		return nil
+	}
+	spent, err := s.spending(ctx, scope)
+	if err != nil {
+		return err
+	}
+	_, resetsAt := budgetPeriod(time.Now())
+	for _, budget := range budgets {
+		if !budget.appliesTo(key) {
+			continue
+		}
+		amount := spent[key]
+		if budget.HardLimitUSD > 0 && amount >= budget.HardLimitUSD {
+			return &BudgetExceededError{
+				Budget:   budget.Name,
+				Scope:    scope,
+				Key:      key,
+				SpentUSD: amount,
+				LimitUSD: budget.HardLimitUSD,
+				ResetsAt: resetsAt,
+			}
+		}
+		if budget.SoftLimitUSD > 0 && amount >= budget.SoftLimitUSD {
+			s.logger.Warn().
+				Str("budget", budget.Name).
+				Str("scope", string(scope)).
+				Str("key", key).
+				Float64("spent_usd", amount).
+				Float64("soft_limit_usd", budget.SoftLimitUSD).
+				Msg("llm budget soft limit reached")
+		}
+	}
+	return nil
+}
+
+// checkActorBudgets checks the tenant and user budgets of the caller.
+func (s *SynthesizerService) checkActorBudgets(ctx context.Context) error {
+	actor := ActorFromContext(ctx)
+	if err := s.checkBudgets(ctx, BudgetScopeTenant, actor.TenantID); err != nil {
+		return err
+	}
+	return s.checkBudgets(ctx, BudgetScopeUser, actor.UserID)
+}
+
+// BudgetStatuses returns the current period spending of every budget. Budgets applying to any key
+// report one status per key that has spent something during the period.
+func (s *SynthesizerService) BudgetStatuses(ctx context.Context) ([]model.BudgetStatus, error) {
+	budgets := s.budgets.list()
+	if len(budgets) == 0 || s.usageRepository == nil {
+		return []model.BudgetStatus{}, nil
+	}
+	start, end := budgetPeriod(time.Now())
+	statuses := make([]model.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		spent, err := s.spending(ctx, budget.Scope)
+		if err != nil {
+			return nil, err
+		}
+		keys := []string{budget.Key}
+		if budget.Key == BudgetAnyKey {
+			keys = keys[:0]
+			for key := range spent {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+		}
+		for _, key := range keys {
+			statuses = append(statuses, model.BudgetStatus{
+				Name:         budget.Name,
+				Scope:        string(budget.Scope),
+				Key:          key,
+				SoftLimitUSD: budget.SoftLimitUSD,
+				HardLimitUSD: budget.HardLimitUSD,
+				SpentUSD:     spent[key],
+				PeriodStart:  start,
+				PeriodEnd:    end,
+				SoftExceeded: budget.SoftLimitUSD > 0 && spent[key] >= budget.SoftLimitUSD,
+				HardExceeded: budget.HardLimitUSD > 0 && spent[key] >= budget.HardLimitUSD,
+			})
+		}
+	}
+	return statuses, nil
+}
+
+// UpdateBudgetLimits changes the limits of a budget until the next restart.
+func (s *SynthesizerService) UpdateBudgetLimits(ctx context.Context, name string, softLimitUSD, hardLimitUSD float64) (Budget, error) {
+	if softLimitUSD < 0 || hardLimitUSD < 0 {
+		return Budget{}, fmt.Errorf("%w: budget limits must be positive", ErrInvalidArgument)
+	}
+	if softLimitUSD > 0 && hardLimitUSD > 0 && softLimitUSD > hardLimitUSD {
+		return Budget{}, fmt.Errorf("%w: soft limit must not be greater than hard limit", ErrInvalidArgument)
+	}
+	s.budgets.mu.Lock()
+	defer s.budgets.mu.Unlock()
+	budget, ok := s.budgets.budgets[name]
+	if !ok {
+		return Budget{}, fmt.Errorf("budget '%s': %w", name, ErrNotFound)
+	}
+	budget.SoftLimitUSD = softLimitUSD
+	budget.HardLimitUSD = hardLimitUSD
+	s.budgets.budgets[name] = budget
+	s.logger.Info().Str("budget", name).
+		Float64("soft_limit_usd", softLimitUSD).
+		Float64("hard_limit_usd", hardLimitUSD).
+		Msg("llm budget limits updated")
+	return budget, nil
+}
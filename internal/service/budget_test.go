@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// usageRecords is an in-memory UsageRepository.
+type usageRecords []model.UsageRecord
+
+func (u *usageRecords) SaveUsage(ctx context.Context, record model.UsageRecord) (model.UsageRecord, error) {
+	record.ID = len(*u) + 1
+	*u = append(*u, record)
+	return record, nil
+}
+
+func (u *usageRecords) ListUsage(ctx context.Context, filter model.UsageFilter) ([]model.UsageRecord, error) {
+	var records []model.UsageRecord
+	for _, record := range *u {
+		if (filter.From.IsZero() || !record.CreatedAt.Before(filter.From)) && (filter.To.IsZero() || record.CreatedAt.Before(filter.To)) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func TestCheckActorBudgets(t *testing.T) {
+	now := time.Now()
+	start, _ := budgetPeriod(now)
+	lastMonth := start.Add(-time.Hour)
+	usage := usageRecords{
+		{CreatedAt: now, TenantID: "acme", UserID: "alice", Provider: "openai", CostUSD: 60},
+		{CreatedAt: now, TenantID: "acme", UserID: "bob", Provider: "openai", CostUSD: 45},
+		{CreatedAt: now, TenantID: "globex", UserID: "carol", Provider: "openai", CostUSD: 10},
+		{CreatedAt: lastMonth, TenantID: "globex", UserID: "carol", Provider: "openai", CostUSD: 500},
+	}
+	budgets := []Budget{
+		{Name: "per-tenant", Scope: BudgetScopeTenant, Key: BudgetAnyKey, SoftLimitUSD: 80, HardLimitUSD: 100},
+		{Name: "bob", Scope: BudgetScopeUser, Key: "bob", HardLimitUSD: 40},
+	}
+	tests := []struct {
+		name       string
+		actor      Actor
+		wantBudget string
+	}{
+		{"tenant over its hard limit", Actor{TenantID: "acme", UserID: "alice"}, "per-tenant"},
+		{"other tenant under its limit", Actor{TenantID: "globex", UserID: "carol"}, ""},
+		{"anonymous actor", Actor{}, ""},
+		{"user over its own limit", Actor{TenantID: "globex", UserID: "bob"}, "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSynthesizerService(nil, nil, nil, WithUsageTracking(&usage, nil), WithBudgets(budgets))
+			err := s.checkActorBudgets(WithActor(context.Background(), tt.actor))
+			var exceeded *BudgetExceededError
+			switch {
+			case tt.wantBudget == "" && err != nil:
+				t.Fatalf("checkActorBudgets() = %v, want nil", err)
+			case tt.wantBudget != "" && !errors.As(err, &exceeded):
+				t.Fatalf("checkActorBudgets() = %v, want budget %s exceeded", err, tt.wantBudget)
+			case tt.wantBudget != "" && exceeded.Budget != tt.wantBudget:
+				t.Fatalf("exceeded budget = %s, want %s", exceeded.Budget, tt.wantBudget)
+			}
+			if err != nil && !errors.Is(err, ErrBudgetExceeded) {
+				t.Errorf("error %v does not match ErrBudgetExceeded", err)
+			}
+		})
+	}
+}
+
+func TestBudgetStatuses(t *testing.T) {
+	now := time.Now()
+	usage := usageRecords{
+		{CreatedAt: now, TenantID: "acme", CostUSD: 85},
+		{CreatedAt: now, TenantID: "globex", CostUSD: 10},
+		{CreatedAt: now, TenantID: "globex", CostUSD: 5},
+	}
+	s := NewSynthesizerService(nil, nil, nil, WithUsageTracking(&usage, nil), WithBudgets([]Budget{
+		{Name: "per-tenant", Scope: BudgetScopeTenant, Key: BudgetAnyKey, SoftLimitUSD: 80, HardLimitUSD: 100},
+	}))
+	statuses, err := s.BudgetStatuses(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want one per tenant: %+v", len(statuses), statuses)
+	}
+	acme, globex := statuses[0], statuses[1]
+	if acme.Key != "acme" || acme.SpentUSD != 85 || !acme.SoftExceeded || acme.HardExceeded {
+		t.Errorf("acme status = %+v", acme)
+	}
+	if globex.Key != "globex" || globex.SpentUSD != 15 || globex.SoftExceeded {
+		t.Errorf("globex status = %+v", globex)
+	}
+}
+
+func TestUpdateBudgetLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		budget     string
+		soft, hard float64
+		wantErr    error
+	}{
+		{"valid limits", "per-tenant", 50, 60, nil},
+		{"soft limit only", "per-tenant", 50, 0, nil},
+		{"negative limit", "per-tenant", -1, 60, ErrInvalidArgument},
+		{"soft above hard", "per-tenant", 70, 60, ErrInvalidArgument},
+		{"unknown budget", "missing", 50, 60, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSynthesizerService(nil, nil, nil, WithBudgets([]Budget{
+				{Name: "per-tenant", Scope: BudgetScopeTenant, Key: BudgetAnyKey, SoftLimitUSD: 80, HardLimitUSD: 100},
+			}))
+			budget, err := s.UpdateBudgetLimits(context.Background(), tt.budget, tt.soft, tt.hard)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("UpdateBudgetLimits() = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (budget.SoftLimitUSD != tt.soft || budget.HardLimitUSD != tt.hard) {
+				t.Errorf("limits = %v/%v, want %v/%v", budget.SoftLimitUSD, budget.HardLimitUSD, tt.soft, tt.hard)
+			}
+		})
+	}
+}
+
+func TestUsageReport(t *testing.T) {
+	usage := usageRecords{
+		{CreatedAt: time.Now(), Operation: "parse_resume", UploadedBytes: 1024},
+		{CreatedAt: time.Now(), Operation: "parse_resume", InputTokens: 1000, OutputTokens: 200, CostUSD: 0.5},
+		{CreatedAt: time.Now(), Operation: "adapt_resume", InputTokens: 3000, OutputTokens: 900, CostUSD: 1.5},
+	}
+	s := NewSynthesizerService(nil, nil, nil, WithUsageTracking(&usage, nil))
+	report, err := s.UsageReport(context.Background(), "operation", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.UsageReportRow{
+		{Key: "adapt_resume", Calls: 1, InputTokens: 3000, OutputTokens: 900, CostUSD: 1.5},
+		{Key: "parse_resume", Calls: 1, InputTokens: 1000, OutputTokens: 200, UploadedBytes: 1024, CostUSD: 0.5},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("report = %+v, want %+v", report, want)
+	}
+	for i := range want {
+		if report[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, report[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// ExtractionCache stores provider outputs by content-addressed key.
+type ExtractionCache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// WithExtractionCache caches parse results for ttl, a zero ttl keeping them forever.
+func WithExtractionCache(cache ExtractionCache, ttl time.Duration) Option {
+	return func(s *SynthesizerService) {
+		s.cache = cache
+		s.cacheTTL = ttl
+	}
+}
+
+// ParseOptions tunes how a document is parsed.
+type ParseOptions struct {
+	// Provider forces a provider instead of the configured chain of the operation.
+	Provider LLMProviderName
+	// ForceRefresh ignores any cached result and replaces it with a fresh one.
+	ForceRefresh bool
+}
+
+// extractionCacheKey identifies a provider output by everything that influences it, including
+// whether the provider was sent the document or its pseudonymized text.
+func extractionCacheKey(ctx context.Context, content []byte, op Operation, name LLMProviderName, provider LLMProvider, pseudonymized bool) string {
+	hash := sha256.New()
+	for _, part := range [][]byte{
+		content,
+		[]byte(op),
+		[]byte(name),
+		[]byte(provider.ModelName()),
+		[]byte(provider.PromptVersion(op, LocaleFromContext(ctx))),
+		[]byte(strconv.FormatBool(pseudonymized)),
+	} {
+		hash.Write(part)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// lookupExtraction returns the cached output of the first provider of the chain that has one.
+// It runs before the budget and circuit breaker checks, since a cached result costs nothing and
+// needs no provider to be up.
+func lookupExtraction[T any](
+	ctx context.Context,
+	s *SynthesizerService,
+	op Operation,
+	providerName LLMProviderName,
+	content []byte,
+	opts ParseOptions,
+) (T, LLMProviderName, bool) {
+	var zero T
+	if s.cache == nil || opts.ForceRefresh {
+		return zero, "", false
+	}
+	chain, err := s.routing.chain(op, providerName)
+	if err != nil {
+		return zero, "", false
+	}
+	for _, name := range chain {
+		provider, err := s.describeProvider(name)
+		if err != nil {
+			continue
+		}
+		key := extractionCacheKey(ctx, content, op, name, provider, s.pseudonymized[name])
+		logger := s.logger.With().Str("operation", string(op)).Str("provider", string(name)).Str("key", key).Logger()
+		raw, ok, err := s.cache.Get(ctx, key)
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to read extraction cache")
+		}
+		if !ok {
+			continue
+		}
+		var cached T
+		if err := json.Unmarshal(raw, &cached); err != nil {
+			logger.Warn().Err(err).Msg("ignoring unreadable extraction cache entry")
+			continue
+		}
+		logger.Debug().Msg("extraction cache hit")
+		return cached, name, true
+	}
+	return zero, "", false
+}
+
+// cachedExtraction calls extract and caches its result, for lookupExtraction to find. Cache
+// failures are logged and never fail the call.
+func cachedExtraction[T any](
+	ctx context.Context,
+	s *SynthesizerService,
+	op Operation,
+	name LLMProviderName,
+	provider LLMProvider,
+	content []byte,
+	extract func(ctx context.Context) (T, error),
+) (T, error) {
+	result, err := extract(ctx)
+	if err != nil || s.cache == nil {
+		return result, err
+	}
+	key := extractionCacheKey(ctx, content, op, name, provider, s.pseudonymized[name])
+	raw, err := json.Marshal(result)
+	if err == nil {
+		err = s.cache.Set(ctx, key, raw, s.cacheTTL)
+	}
+	if err != nil {
+		s.logger.Warn().Err(err).Str("operation", string(op)).Str("provider", string(name)).Str("key", key).
+			Msg("failed to write extraction cache")
+	}
+	return result, nil
+}
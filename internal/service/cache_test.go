@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// stubProvider is an LLMProvider that parses every resume into the same result.
+type stubProvider struct {
+	model, prompt string
+	resume        model.CandidateResume
+}
+
+func (p *stubProvider) ModelName() string { return p.model }
+
+func (p *stubProvider) PromptVersion(op Operation, locale string) string {
+	return p.prompt + "/" + locale
+}
+
+func (p *stubProvider) ParseResume(ctx context.Context, file model.File) (model.CandidateResume, error) {
+	return p.resume, nil
+}
+
+func (p *stubProvider) ParseJobAd(ctx context.Context, file model.File) (model.JobAd, error) {
+	return model.JobAd{}, errors.New("not implemented")
+}
+
+func (p *stubProvider) AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error) {
+	return model.ResumeAdaptation{}, errors.New("not implemented")
+}
+
+// stubFactory refuses the providers listed in down, as the circuit breaker does, but still
+// describes them.
+type stubFactory struct {
+	providers map[LLMProviderName]*stubProvider
+	down      map[LLMProviderName]bool
+}
+
+func (f stubFactory) GetProvider(name LLMProviderName) (LLMProvider, error) {
+	if f.down[name] {
+		return nil, ErrProviderUnavailable
+	}
+	return f.DescribeProvider(name)
+}
+
+func (f stubFactory) DescribeProvider(name LLMProviderName) (LLMProvider, error) {
+	provider, ok := f.providers[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return provider, nil
+}
+
+// memoryCache is an ExtractionCache that ignores ttl.
+type memoryCache map[string][]byte
+
+func (c memoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, ok := c[key]
+	return value, ok, nil
+}
+
+func (c memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func TestExtractionCacheKey(t *testing.T) {
+	ctx := WithLocale(context.Background(), "fr")
+	provider := &stubProvider{model: "gpt", prompt: "v1"}
+	base := extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "openai", provider, false)
+	if again := extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "openai", provider, false); again != base {
+		t.Fatalf("key is not stable: %s != %s", again, base)
+	}
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"content", extractionCacheKey(ctx, []byte("other resume"), OperationParseResume, "openai", provider, false)},
+		{"operation", extractionCacheKey(ctx, []byte("resume"), OperationParseJobAd, "openai", provider, false)},
+		{"provider", extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "mistral", provider, false)},
+		{"model", extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "openai", &stubProvider{model: "gpt-mini", prompt: "v1"}, false)},
+		{"prompt", extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "openai", &stubProvider{model: "gpt", prompt: "v2"}, false)},
+		{"locale", extractionCacheKey(WithLocale(ctx, "en"), []byte("resume"), OperationParseResume, "openai", provider, false)},
+		{"pseudonymization", extractionCacheKey(ctx, []byte("resume"), OperationParseResume, "openai", provider, true)},
+	}
+	for _, tt := range tests {
+		if tt.key == base {
+			t.Errorf("changing the %s keeps the same cache key", tt.name)
+		}
+	}
+}
+
+func TestLookupExtraction(t *testing.T) {
+	primary := &stubProvider{model: "gpt", prompt: "v1", resume: model.CandidateResume{FullName: "from primary"}}
+	fallback := &stubProvider{model: "mistral", prompt: "v1", resume: model.CandidateResume{FullName: "from fallback"}}
+	routing := Routing{OperationParseResume: {Providers: []LLMProviderName{"primary", "fallback"}}}
+	content := []byte("resume")
+
+	tests := []struct {
+		name         string
+		cached       []LLMProviderName
+		down         map[LLMProviderName]bool
+		opts         ParseOptions
+		wantProvider LLMProviderName
+	}{
+		{"nothing cached", nil, nil, ParseOptions{}, ""},
+		{"primary cached", []LLMProviderName{"primary", "fallback"}, nil, ParseOptions{}, "primary"},
+		{"fallback cached", []LLMProviderName{"fallback"}, nil, ParseOptions{}, "fallback"},
+		{"provider down", []LLMProviderName{"primary"}, map[LLMProviderName]bool{"primary": true}, ParseOptions{}, "primary"},
+		{"forced provider", []LLMProviderName{"primary", "fallback"}, nil, ParseOptions{Provider: "fallback"}, "fallback"},
+		{"force refresh", []LLMProviderName{"primary"}, nil, ParseOptions{ForceRefresh: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := stubFactory{
+				providers: map[LLMProviderName]*stubProvider{"primary": primary, "fallback": fallback},
+				down:      tt.down,
+			}
+			s := NewSynthesizerService(factory, nil, routing, WithExtractionCache(memoryCache{}, 0))
+			ctx := context.Background()
+			for _, name := range tt.cached {
+				provider := factory.providers[name]
+				_, err := cachedExtraction(ctx, s, OperationParseResume, name, provider, content,
+					func(ctx context.Context) (model.CandidateResume, error) {
+						return provider.ParseResume(ctx, model.File{Content: content})
+					})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resume, name, ok := lookupExtraction[model.CandidateResume](ctx, s, OperationParseResume, tt.opts.Provider, content, tt.opts)
+			if ok != (tt.wantProvider != "") || name != tt.wantProvider {
+				t.Fatalf("lookupExtraction() found %t from %q, want %q", ok, name, tt.wantProvider)
+			}
+			if ok && resume.FullName != factory.providers[name].resume.FullName {
+				t.Errorf("cached resume = %q, want the one of %s", resume.FullName, name)
+			}
+		})
+	}
+}
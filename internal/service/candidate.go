@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+const (
+	// minPhoneDigits is how many digits a phone number has at least, shorter ones being
+	// extensions or partial numbers that cannot identify anyone.
+	minPhoneDigits = 8
+	// maxCountryCodeDigits is how long a country calling code is at most.
+	maxCountryCodeDigits = 3
+	// minSharedEmployers is how many employers in common make two employment histories alike,
+	// whatever their length.
+	minSharedEmployers = 2
+)
+
+// accents removes the accents of person names, which resumes do not always write.
+var accents = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "ÿ", "y",
+)
+
+// attachCandidate links a newly saved resume to its candidate: an existing candidate with the
+// same name and the same email, phone or employment history, or else a new candidate, which
+// records the existing candidates that only partly match as possible duplicates.
+func (s *SynthesizerService) attachCandidate(ctx context.Context, resume model.CandidateResume) (model.CandidateResume, error) {
+	s.candidatesMu.Lock()
+	defer s.candidatesMu.Unlock()
+
+	candidates, err := s.repository.ListCandidates(ctx)
+	if err != nil {
+		return resume, err
+	}
+	resumes, err := s.repository.ListResumes(ctx)
+	if err != nil {
+		return resume, err
+	}
+	byCandidate := make(map[int][]model.CandidateResume)
+	for _, r := range resumes {
+		if r.ID != resume.ID {
+			byCandidate[r.CandidateID] = append(byCandidate[r.CandidateID], r)
+		}
+	}
+
+	var duplicates []model.DuplicateMatch
+	for _, candidate := range candidates {
+		reasons, same := compareCandidate(resume, candidate, byCandidate[candidate.ID])
+		if same {
+			addResume(&candidate, resume)
+			if _, err := s.repository.UpdateCandidate(ctx, candidate); err != nil {
+				return resume, err
+			}
+			resume.CandidateID = candidate.ID
+			return s.repository.UpdateResume(ctx, resume)
+		}
+		if len(reasons) > 0 {
+			duplicates = append(duplicates, model.DuplicateMatch{CandidateID: candidate.ID, Reasons: reasons})
+		}
+	}
+
+	now := time.Now()
+	candidate := model.Candidate{
+		FullName:           resume.FullName,
+		PossibleDuplicates: duplicates,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	addResume(&candidate, resume)
+	candidate, err = s.repository.SaveCandidate(ctx, candidate)
+	if err != nil {
+		return resume, err
+	}
+	for _, duplicate := range duplicates {
+		other, err := s.repository.GetCandidate(ctx, duplicate.CandidateID)
+		if err != nil {
+			return resume, err
+		}
+		other.PossibleDuplicates = append(other.PossibleDuplicates, model.DuplicateMatch{CandidateID: candidate.ID, Reasons: duplicate.Reasons})
+		if _, err := s.repository.UpdateCandidate(ctx, other); err != nil {
+			return resume, err
+		}
+	}
+	if len(duplicates) > 0 {
+		s.logger.Info().Int("candidate_id", candidate.ID).Int("possible_duplicates", len(duplicates)).
+			Msg("new candidate may duplicate existing ones")
+	}
+	resume.CandidateID = candidate.ID
+	return s.repository.UpdateResume(ctx, resume)
+}
+
+// compareCandidate tells why resume may belong to candidate, whose other resumes are given, and
+// whether the evidence is strong enough to attach it: the same name along with a shared email,
+// phone or similar employment history. Contact details alone are shared by relatives and reused
+// by agencies, so they only make a possible duplicate.
+func compareCandidate(resume model.CandidateResume, candidate model.Candidate, resumes []model.CandidateResume) ([]string, bool) {
+	var reasons []string
+	sameEmail := resume.Email != "" && slices.ContainsFunc(candidate.Emails, func(email string) bool {
+		return emailKey(email) == emailKey(resume.Email)
+	})
+	if sameEmail {
+		reasons = append(reasons, "same email")
+	}
+	samePhone := slices.ContainsFunc(candidate.Phones, func(phone string) bool {
+		return samePhone(phone, resume.Phone)
+	})
+	if samePhone {
+		reasons = append(reasons, "same phone")
+	}
+	sameName := nameKey(resume.FullName) != "" && nameKey(resume.FullName) == nameKey(candidate.FullName)
+	if sameName {
+		reasons = append(reasons, "same name")
+	}
+
+	employers := employerKeys(resume)
+	others := make(map[string]bool)
+	for _, r := range resumes {
+		for employer := range employerKeys(r) {
+			others[employer] = true
+		}
+	}
+	shared := 0
+	for employer := range employers {
+		if others[employer] {
+			shared++
+		}
+	}
+	similarHistory := shared > 0 && (shared >= minSharedEmployers || 2*shared >= min(len(employers), len(others)))
+	if similarHistory {
+		reasons = append(reasons, fmt.Sprintf("%d employers in common", shared))
+	}
+	return reasons, sameName && (sameEmail || samePhone || similarHistory)
+}
+
+// addResume adds resume to candidate, along with its contact details and, when the candidate
+// has none yet, its rate, billing mode and availability.
+func addResume(candidate *model.Candidate, resume model.CandidateResume) {
+	if !slices.Contains(candidate.ResumeIDs, resume.ID) {
+		candidate.ResumeIDs = append(candidate.ResumeIDs, resume.ID)
+	}
+	mergeString(&candidate.FullName, resume.FullName)
+	candidate.Emails = appendContact(candidate.Emails, resume.Email, sameEmail)
+	candidate.Phones = appendContact(candidate.Phones, resume.Phone, samePhone)
+	if candidate.Rate == nil && resume.Rate.Amount > 0 {
+		rate := resume.Rate
+		candidate.Rate, candidate.DailyRate = &rate, resume.DailyRate
+	}
+	mergeString(&candidate.BillingMode, resume.BillingMode)
+	mergeString(&candidate.Availability, resume.Availability)
+	candidate.UpdatedAt = time.Now()
+}
+
+// appendContact appends value to contacts unless it is empty or the same as one of them.
+func appendContact(contacts []string, value string, same func(a, b string) bool) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || slices.ContainsFunc(contacts, func(c string) bool { return same(c, value) }) {
+		return contacts
+	}
+	return append(contacts, value)
+}
+
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func sameEmail(a, b string) bool {
+	return emailKey(a) != "" && emailKey(a) == emailKey(b)
+}
+
+// phoneKey returns the digits of a phone number, prefixed with + when it is written in
+// international form, with a + or 00, or an empty string when it is too short to be a number.
+func phoneKey(phone string) string {
+	phone = strings.TrimSpace(phone)
+	international := strings.HasPrefix(phone, "+")
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if !international && strings.HasPrefix(digits, "00") {
+		international, digits = true, digits[2:]
+	}
+	if len(digits) < minPhoneDigits {
+		return ""
+	}
+	if international {
+		return "+" + digits
+	}
+	return digits
+}
+
+// samePhone tells whether two phone numbers are the same. A number in national form, starting
+// with a trunk 0, is the same as an international one that ends with its other digits after a
+// country code, as 06 12 34 56 78 and +33 6 12 34 56 78.
+func samePhone(a, b string) bool {
+	a, b = phoneKey(a), phoneKey(b)
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if strings.HasPrefix(b, "+") {
+		a, b = b, a
+	}
+	if !strings.HasPrefix(a, "+") || !strings.HasPrefix(b, "0") {
+		return false
+	}
+	national := b[1:]
+	countryCode := len(a) - 1 - len(national)
+	return countryCode >= 1 && countryCode <= maxCountryCodeDigits && strings.HasSuffix(a, national)
+}
+
+// nameKey compares person names regardless of case, accents and word order, so that
+// "DUPONT Jean" and "Jean Dupont" are the same name.
+func nameKey(name string) string {
+	words := strings.Fields(accents.Replace(normalizeClaim(name)))
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+func employerKeys(resume model.CandidateResume) map[string]bool {
+	keys := make(map[string]bool)
+	for _, experience := range resume.Experiences {
+		if key := normalizeClaim(experience.CompanyName); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+func (s *SynthesizerService) GetCandidate(ctx context.Context, candidateID int) (model.Candidate, error) {
+	return s.repository.GetCandidate(ctx, candidateID)
+}
+
+func (s *SynthesizerService) ListCandidates(ctx context.Context) ([]model.Candidate, error) {
+	return s.repository.ListCandidates(ctx)
+}
+
+// UpdateCandidate updates the name, contact details and internal data of a candidate. Its
+// resumes and possible duplicates only change through merges.
+func (s *SynthesizerService) UpdateCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error) {
+	s.candidatesMu.Lock()
+	defer s.candidatesMu.Unlock()
+
+	rate, err := s.candidateRate(candidate.Rate)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	billingMode := normalizeBillingMode(candidate.BillingMode)
+	if billingMode == "" && strings.TrimSpace(candidate.BillingMode) != "" {
+		return model.Candidate{}, fmt.Errorf("%w: billing_mode must be one of %s",
+			ErrInvalidArgument, strings.Join(model.BillingModes, ", "))
+	}
+	existing, err := s.repository.GetCandidate(ctx, candidate.ID)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	existing.FullName = candidate.FullName
+	existing.Emails = candidate.Emails
+	existing.Phones = candidate.Phones
+	existing.Rate, existing.DailyRate = rate, nil
+	if rate != nil {
+		existing.DailyRate = s.dailyRate(*rate)
+	}
+	existing.BillingMode = billingMode
+	existing.Availability = candidate.Availability
+	existing.Notes = candidate.Notes
+	existing.UpdatedAt = time.Now()
+	return s.repository.UpdateCandidate(ctx, existing)
+}
+
+// candidateRate checks and normalizes a rate entered for a candidate. A nil or zero rate clears
+// the rate.
+func (s *SynthesizerService) candidateRate(rate *model.Rate) (*model.Rate, error) {
+	if rate == nil || rate.Amount == 0 {
+		return nil, nil
+	}
+	if rate.Amount < 0 {
+		return nil, fmt.Errorf("%w: the rate amount must be positive", ErrInvalidArgument)
+	}
+	if rate.Currency != "" && normalizeCurrency(rate.Currency) == "" {
+		return nil, fmt.Errorf("%w: the rate currency must be an ISO 4217 code", ErrInvalidArgument)
+	}
+	if rate.Unit != "" && normalizeUnit(rate.Unit) == "" {
+		return nil, fmt.Errorf("%w: the rate unit must be one of %s", ErrInvalidArgument, strings.Join(model.RateUnits, ", "))
+	}
+	normalized := s.normalizeRate(*rate)
+	return &normalized, nil
+}
+
+// MergeCandidates moves the resumes and contact details of the candidate sourceID to the
+// candidate targetID, completes the internal data of the target with those of the source, and
+// deletes the source.
+func (s *SynthesizerService) MergeCandidates(ctx context.Context, targetID, sourceID int) (model.Candidate, error) {
+	if targetID == sourceID {
+		return model.Candidate{}, fmt.Errorf("%w: a candidate cannot be merged into itself", ErrInvalidArgument)
+	}
+	s.candidatesMu.Lock()
+	defer s.candidatesMu.Unlock()
+
+	target, err := s.repository.GetCandidate(ctx, targetID)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	source, err := s.repository.GetCandidate(ctx, sourceID)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+
+	resumes := make([]model.CandidateResume, 0, len(source.ResumeIDs))
+	for _, resumeID := range source.ResumeIDs {
+		resume, err := s.repository.GetResume(ctx, resumeID)
+		if err != nil {
+			return model.Candidate{}, err
+		}
+		resumes = append(resumes, resume)
+		if !slices.Contains(target.ResumeIDs, resumeID) {
+			target.ResumeIDs = append(target.ResumeIDs, resumeID)
+		}
+	}
+	for _, email := range source.Emails {
+		target.Emails = appendContact(target.Emails, email, sameEmail)
+	}
+	for _, phone := range source.Phones {
+		target.Phones = appendContact(target.Phones, phone, samePhone)
+	}
+	mergeString(&target.FullName, source.FullName)
+	if target.Rate == nil {
+		target.Rate, target.DailyRate = source.Rate, source.DailyRate
+	}
+	mergeString(&target.BillingMode, source.BillingMode)
+	mergeString(&target.Availability, source.Availability)
+	target.Notes = mergeText(target.Notes, source.Notes, "\n")
+	for _, duplicate := range source.PossibleDuplicates {
+		if duplicate.CandidateID != target.ID && !slices.ContainsFunc(target.PossibleDuplicates, func(d model.DuplicateMatch) bool {
+			return d.CandidateID == duplicate.CandidateID
+		}) {
+			target.PossibleDuplicates = append(target.PossibleDuplicates, duplicate)
+		}
+	}
+	target.PossibleDuplicates = withoutDuplicate(target.PossibleDuplicates, source.ID)
+	target.UpdatedAt = time.Now()
+
+	// The target is saved first and the source deleted last, so that a failure midway never loses
+	// the resumes or details of the source.
+	merged, err := s.repository.UpdateCandidate(ctx, target)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	for _, resume := range resumes {
+		resume.CandidateID = target.ID
+		if _, err := s.repository.UpdateResume(ctx, resume); err != nil {
+			return model.Candidate{}, err
+		}
+	}
+	if err := s.forgetDuplicate(ctx, source.ID); err != nil {
+		return model.Candidate{}, err
+	}
+	if err := s.repository.DeleteCandidate(ctx, source.ID); err != nil {
+		return model.Candidate{}, err
+	}
+	s.logger.Info().Int("candidate_id", target.ID).Int("merged_candidate_id", source.ID).Msg("candidates merged")
+	return merged, nil
+}
+
+// DismissDuplicate records that two candidates reported as possible duplicates are different
+// people.
+func (s *SynthesizerService) DismissDuplicate(ctx context.Context, candidateID, otherID int) (model.Candidate, error) {
+	s.candidatesMu.Lock()
+	defer s.candidatesMu.Unlock()
+
+	candidate, err := s.repository.GetCandidate(ctx, candidateID)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	other, err := s.repository.GetCandidate(ctx, otherID)
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	other.PossibleDuplicates = withoutDuplicate(other.PossibleDuplicates, candidate.ID)
+	if _, err := s.repository.UpdateCandidate(ctx, other); err != nil {
+		return model.Candidate{}, err
+	}
+	candidate.PossibleDuplicates = withoutDuplicate(candidate.PossibleDuplicates, other.ID)
+	return s.repository.UpdateCandidate(ctx, candidate)
+}
+
+// forgetDuplicate removes a deleted candidate from the possible duplicates of the others.
+func (s *SynthesizerService) forgetDuplicate(ctx context.Context, candidateID int) error {
+	candidates, err := s.repository.ListCandidates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		kept := withoutDuplicate(candidate.PossibleDuplicates, candidateID)
+		if len(kept) == len(candidate.PossibleDuplicates) {
+			continue
+		}
+		candidate.PossibleDuplicates = kept
+		if _, err := s.repository.UpdateCandidate(ctx, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutDuplicate returns a copy of duplicates without candidateID, leaving duplicates intact as
+// it may be shared with the repository.
+func withoutDuplicate(duplicates []model.DuplicateMatch, candidateID int) []model.DuplicateMatch {
+	return slices.DeleteFunc(slices.Clone(duplicates), func(d model.DuplicateMatch) bool { return d.CandidateID == candidateID })
+}
+
+// detachResume removes a deleted resume from its candidate.
+func (s *SynthesizerService) detachResume(ctx context.Context, resume model.CandidateResume) error {
+	if resume.CandidateID == 0 {
+		return nil
+	}
+	s.candidatesMu.Lock()
+	defer s.candidatesMu.Unlock()
+
+	candidate, err := s.repository.GetCandidate(ctx, resume.CandidateID)
+	if err != nil {
+		return err
+	}
+	candidate.ResumeIDs = slices.DeleteFunc(candidate.ResumeIDs, func(id int) bool { return id == resume.ID })
+	candidate.UpdatedAt = time.Now()
+	_, err = s.repository.UpdateCandidate(ctx, candidate)
+	return err
+}
+
+// AdaptCandidate adapts the resumes of a candidate to a job ad, like AdaptResume.
+func (s *SynthesizerService) AdaptCandidate(ctx context.Context, jobAdID, candidateID int, providerName LLMProviderName) (model.CandidateAdaptedResume, error) {
+	candidate, err := s.repository.GetCandidate(ctx, candidateID)
+	if err != nil {
+		return model.CandidateAdaptedResume{}, fmt.Errorf("failed to retrieve candidate with ID %d: %w", candidateID, err)
+	}
+	if len(candidate.ResumeIDs) == 0 {
+		return model.CandidateAdaptedResume{}, fmt.Errorf("%w: candidate %d has no resume", ErrInvalidArgument, candidateID)
+	}
+	return s.AdaptResume(ctx, jobAdID, candidate.ResumeIDs, providerName)
+}
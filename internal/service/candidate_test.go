@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestPhoneKey(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"06 12 34 56 78", "0612345678"},
+		{"+33 6 12 34 56 78", "+33612345678"},
+		{"0033 6 12 34 56 78", "+33612345678"},
+		{"+33 (0)6 12 34 56 78", "+330612345678"},
+		{"poste 1234", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			if got := phoneKey(tt.phone); got != tt.want {
+				t.Errorf("phoneKey(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamePhone(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"same national number", "06 12 34 56 78", "06.12.34.56.78", true},
+		{"national and international forms", "06 12 34 56 78", "+33 6 12 34 56 78", true},
+		{"international forms", "0033 6 12 34 56 78", "+33 6 12 34 56 78", true},
+		{"same digits in another country", "+33 6 12 34 56 78", "+41 6 12 34 56 78", false},
+		{"national number of another length", "06 12 34 56 78", "+33 16 12 34 56 78", true},
+		{"different numbers", "06 12 34 56 78", "06 12 34 56 79", false},
+		{"too short", "1234", "1234", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := samePhone(tt.a, tt.b); got != tt.want {
+				t.Errorf("samePhone(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareCandidate(t *testing.T) {
+	candidate := model.Candidate{
+		FullName: "Jean Dupont",
+		Emails:   []string{"jean.dupont@example.com"},
+		Phones:   []string{"+33 6 12 34 56 78"},
+	}
+	history := []model.CandidateResume{{Experiences: []model.Experience{{CompanyName: "Acme"}, {CompanyName: "Globex"}}}}
+	tests := []struct {
+		name        string
+		resume      model.CandidateResume
+		wantReasons []string
+		wantSame    bool
+	}{
+		{
+			name:        "same name and email",
+			resume:      model.CandidateResume{FullName: "DUPONT Jean", Email: "Jean.Dupont@example.com"},
+			wantReasons: []string{"same email", "same name"},
+			wantSame:    true,
+		},
+		{
+			name:        "same name and phone",
+			resume:      model.CandidateResume{FullName: "Jean Dupont", Phone: "06 12 34 56 78"},
+			wantReasons: []string{"same phone", "same name"},
+			wantSame:    true,
+		},
+		{
+			name:        "email only",
+			resume:      model.CandidateResume{FullName: "Marie Dupont", Email: "jean.dupont@example.com"},
+			wantReasons: []string{"same email"},
+		},
+		{
+			name:        "phone only",
+			resume:      model.CandidateResume{FullName: "Paul Martin", Phone: "06 12 34 56 78"},
+			wantReasons: []string{"same phone"},
+		},
+		{
+			name:        "same name and history",
+			resume:      model.CandidateResume{FullName: "Jean Dupont", Experiences: []model.Experience{{CompanyName: "ACME"}, {CompanyName: "Globex"}}},
+			wantReasons: []string{"same name", "2 employers in common"},
+			wantSame:    true,
+		},
+		{
+			name:        "name only",
+			resume:      model.CandidateResume{FullName: "Jean Dupont", Experiences: []model.Experience{{CompanyName: "Initech"}}},
+			wantReasons: []string{"same name"},
+		},
+		{
+			name:   "nothing in common",
+			resume: model.CandidateResume{FullName: "Paul Martin", Phone: "+41 6 12 34 56 78"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons, same := compareCandidate(tt.resume, candidate, history)
+			if !slices.Equal(reasons, tt.wantReasons) || same != tt.wantSame {
+				t.Errorf("compareCandidate() = %v, %v, want %v, %v", reasons, same, tt.wantReasons, tt.wantSame)
+			}
+		})
+	}
+}
+
+// candidateStore is a ResumeRepository of candidates and resumes that can fail to update
+// candidates, and records the calls that change them.
+type candidateStore struct {
+	ResumeRepository
+	candidates map[int]model.Candidate
+	resumes    map[int]model.CandidateResume
+	failUpdate int
+	calls      []string
+}
+
+func (r *candidateStore) GetCandidate(ctx context.Context, id int) (model.Candidate, error) {
+	candidate, ok := r.candidates[id]
+	if !ok {
+		return model.Candidate{}, ErrNotFound
+	}
+	return candidate, nil
+}
+
+func (r *candidateStore) ListCandidates(ctx context.Context) ([]model.Candidate, error) {
+	var candidates []model.Candidate
+	for _, candidate := range r.candidates {
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+func (r *candidateStore) UpdateCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error) {
+	if candidate.ID == r.failUpdate {
+		return model.Candidate{}, errors.New("update failed")
+	}
+	r.calls = append(r.calls, "update candidate")
+	r.candidates[candidate.ID] = candidate
+	return candidate, nil
+}
+
+func (r *candidateStore) DeleteCandidate(ctx context.Context, id int) error {
+	r.calls = append(r.calls, "delete candidate")
+	delete(r.candidates, id)
+	return nil
+}
+
+func (r *candidateStore) GetResume(ctx context.Context, id int) (model.CandidateResume, error) {
+	resume, ok := r.resumes[id]
+	if !ok {
+		return model.CandidateResume{}, ErrNotFound
+	}
+	return resume, nil
+}
+
+func (r *candidateStore) UpdateResume(ctx context.Context, resume model.CandidateResume) (model.CandidateResume, error) {
+	r.calls = append(r.calls, "update resume")
+	r.resumes[resume.ID] = resume
+	return resume, nil
+}
+
+func TestMergeCandidates(t *testing.T) {
+	newStore := func() *candidateStore {
+		return &candidateStore{
+			candidates: map[int]model.Candidate{
+				1: {ID: 1, FullName: "Jean Dupont", Emails: []string{"jean@example.com"}, ResumeIDs: []int{10},
+					PossibleDuplicates: []model.DuplicateMatch{{CandidateID: 2}}},
+				2: {ID: 2, FullName: "Jean Dupont", Phones: []string{"06 12 34 56 78"}, ResumeIDs: []int{20},
+					BillingMode: model.BillingFreelance, PossibleDuplicates: []model.DuplicateMatch{{CandidateID: 1}, {CandidateID: 3}}},
+				3: {ID: 3, FullName: "J. Dupont", PossibleDuplicates: []model.DuplicateMatch{{CandidateID: 2}}},
+			},
+			resumes: map[int]model.CandidateResume{
+				10: {ID: 10, CandidateID: 1},
+				20: {ID: 20, CandidateID: 2},
+			},
+		}
+	}
+
+	t.Run("merged", func(t *testing.T) {
+		store := newStore()
+		s := NewSynthesizerService(nil, store, nil)
+		merged, err := s.MergeCandidates(context.Background(), 1, 2)
+		if err != nil {
+			t.Fatalf("MergeCandidates() error = %v", err)
+		}
+		if !slices.Equal(merged.ResumeIDs, []int{10, 20}) || !slices.Equal(merged.Phones, []string{"06 12 34 56 78"}) ||
+			merged.BillingMode != model.BillingFreelance {
+			t.Errorf("MergeCandidates() = %+v, want the resumes, phones and billing mode of both", merged)
+		}
+		if len(merged.PossibleDuplicates) != 1 || merged.PossibleDuplicates[0].CandidateID != 3 {
+			t.Errorf("PossibleDuplicates = %v, want candidate 3 only", merged.PossibleDuplicates)
+		}
+		if _, ok := store.candidates[2]; ok {
+			t.Error("source candidate was not deleted")
+		}
+		if store.resumes[20].CandidateID != 1 {
+			t.Errorf("resume 20 CandidateID = %d, want 1", store.resumes[20].CandidateID)
+		}
+		if len(store.candidates[3].PossibleDuplicates) != 0 {
+			t.Errorf("candidate 3 PossibleDuplicates = %v, want none", store.candidates[3].PossibleDuplicates)
+		}
+		if store.calls[0] != "update candidate" || store.calls[len(store.calls)-1] != "delete candidate" {
+			t.Errorf("calls = %v, want the target updated first and the source deleted last", store.calls)
+		}
+	})
+
+	t.Run("target update fails", func(t *testing.T) {
+		store := newStore()
+		store.failUpdate = 1
+		s := NewSynthesizerService(nil, store, nil)
+		if _, err := s.MergeCandidates(context.Background(), 1, 2); err == nil {
+			t.Fatal("MergeCandidates() error = nil, want the update error")
+		}
+		if len(store.calls) != 0 {
+			t.Errorf("calls = %v, want nothing changed", store.calls)
+		}
+		if store.resumes[20].CandidateID != 2 {
+			t.Errorf("resume 20 CandidateID = %d, want 2", store.resumes[20].CandidateID)
+		}
+	})
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxHeadingRunes is the length above which a line is never considered a section heading.
+const maxHeadingRunes = 60
+
+// WithChunking splits documents whose extracted text is longer than maxChars into chunks that
+// are extracted separately, at most concurrency at a time, and merged into a single result.
+func WithChunking(maxChars, concurrency int) Option {
+	return func(s *SynthesizerService) {
+		s.chunkMaxChars = maxChars
+		s.chunkConcurrency = max(concurrency, 1)
+	}
+}
+
+// extractChunked calls extract once for file when its text fits in a chunk, and otherwise once per
+// chunk of text, merging the partial results in document order. The first chunk that fails
+// cancels the others, and the merged result must pass check, which a single part need not.
+func extractChunked[T any](
+	ctx context.Context,
+	s *SynthesizerService,
+	name LLMProviderName,
+	file model.File,
+	extract func(ctx context.Context, file model.File) (T, error),
+	merge func(parts []T) T,
+	check func(T) []string,
+) (T, error) {
+	var zero T
+	if s.chunkMaxChars <= 0 || utf8.RuneCountInString(file.Text) <= s.chunkMaxChars {
+		return extract(ctx, file)
+	}
+	chunks := splitDocument(file.Text, s.chunkMaxChars)
+	s.logger.Debug().Str("file", file.Name).Int("chunks", len(chunks)).Msg("extracting document by chunks")
+
+	parts := make([]T, len(chunks))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(s.chunkConcurrency)
+	for i, chunk := range chunks {
+		group.Go(func() error {
+			part, err := extract(groupCtx, model.File{
+				ID:        file.ID,
+				Name:      file.Name,
+				Extension: file.Extension,
+				MimeType:  "text/plain",
+				Content:   []byte(chunk),
+				Text:      chunk,
+				Part:      i + 1,
+				Parts:     len(chunks),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to extract part %d of %d: %w", i+1, len(chunks), err)
+			}
+			parts[i] = part
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return zero, err
+	}
+
+	merged := merge(parts)
+	if problems := check(merged); len(problems) > 0 {
+		return zero, &LLMError{
+			Provider: name,
+			Kind:     LLMErrorInvalidOutput,
+			Err:      fmt.Errorf("the %d merged parts are invalid: %s", len(chunks), strings.Join(problems, "; ")),
+		}
+	}
+	return merged, nil
+}
+
+// splitDocument cuts text into chunks of at most maxChars runes. Chunks end between paragraphs,
+// and preferably before a section heading once they are half full. Paragraphs that are too long
+// are cut between lines, and lines between words.
+func splitDocument(text string, maxChars int) []string {
+	var blocks []string
+	paragraphs := strings.Split(text, "\n\n")
+	for i := 0; i < len(paragraphs); i++ {
+		paragraph := paragraphs[i]
+		// A heading standing alone is kept with the paragraph it introduces.
+		if isHeading(paragraph) && !strings.Contains(paragraph, "\n") && i+1 < len(paragraphs) {
+			i++
+			paragraph += "\n" + paragraphs[i]
+		}
+		blocks = append(blocks, splitBlock(paragraph, maxChars)...)
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentChars := 0
+	for _, block := range blocks {
+		blockChars := utf8.RuneCountInString(block)
+		full := currentChars > 0 && currentChars+2+blockChars > maxChars
+		newSection := currentChars >= maxChars/2 && isHeading(block)
+		if full || newSection {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentChars = 0
+		}
+		if currentChars > 0 {
+			current.WriteString("\n\n")
+			currentChars += 2
+		}
+		current.WriteString(block)
+		currentChars += blockChars
+	}
+	if currentChars > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitBlock cuts a paragraph longer than maxChars runes between lines, or between words for
+// lines that are too long themselves.
+func splitBlock(block string, maxChars int) []string {
+	if utf8.RuneCountInString(block) <= maxChars {
+		return []string{block}
+	}
+	sep, pieces := "\n", strings.Split(block, "\n")
+	if len(pieces) == 1 {
+		sep, pieces = " ", strings.Fields(block)
+	}
+	if len(pieces) == 1 {
+		runes := []rune(block)
+		return append([]string{string(runes[:maxChars])}, splitBlock(string(runes[maxChars:]), maxChars)...)
+	}
+	var out []string
+	var current []string
+	currentChars := 0
+	for _, piece := range pieces {
+		pieceChars := utf8.RuneCountInString(piece)
+		if currentChars > 0 && currentChars+len(sep)+pieceChars > maxChars {
+			out = append(out, strings.Join(current, sep))
+			current, currentChars = nil, 0
+		}
+		if pieceChars > maxChars {
+			out = append(out, splitBlock(piece, maxChars)...)
+			continue
+		}
+		if currentChars > 0 {
+			currentChars += len(sep)
+		}
+		current = append(current, piece)
+		currentChars += pieceChars
+	}
+	if currentChars > 0 {
+		out = append(out, strings.Join(current, sep))
+	}
+	return out
+}
+
+// isHeading reports whether a block starts with a short line written in capitals or ending with
+// a colon, as section titles usually are.
+func isHeading(block string) bool {
+	line, _, _ := strings.Cut(strings.TrimSpace(block), "\n")
+	if line == "" || utf8.RuneCountInString(line) > maxHeadingRunes {
+		return false
+	}
+	if strings.HasSuffix(line, ":") {
+		return true
+	}
+	hasLetter := false
+	for _, r := range line {
+		if unicode.IsLower(r) {
+			return false
+		}
+		hasLetter = hasLetter || unicode.IsLetter(r)
+	}
+	return hasLetter
+}
+
+// mergeResumes combines the resumes extracted from consecutive chunks. Single values are taken
+// from the first chunk that has them, and lists are concatenated without duplicates.
+func mergeResumes(parts []model.CandidateResume) model.CandidateResume {
+	var merged model.CandidateResume
+	for _, part := range parts {
+		mergeString(&merged.FullName, part.FullName)
+		mergeString(&merged.Email, part.Email)
+		mergeString(&merged.Phone, part.Phone)
+		mergeString(&merged.Description, part.Description)
+		mergeString(&merged.ShortDescription, part.ShortDescription)
+		mergeString(&merged.Location, part.Location)
+		mergeString(&merged.Availability, part.Availability)
+		mergeString(&merged.RemotePreference, part.RemotePreference)
+		mergeString(&merged.MissionDuration, part.MissionDuration)
+		if merged.Rate.Amount == 0 {
+			merged.Rate = part.Rate
+		}
+		mergeString(&merged.BillingMode, part.BillingMode)
+		merged.Certifications = appendUnique(merged.Certifications, part.Certifications...)
+		for _, skill := range part.Skills {
+			merged.Skills = mergeSkills(merged.Skills, skill)
+		}
+		for _, experience := range part.Experiences {
+			merged.Experiences = mergeExperience(merged.Experiences, experience)
+		}
+		for _, education := range part.Education {
+			merged.Education = mergeEducation(merged.Education, education)
+		}
+		for _, language := range part.Languages {
+			merged.Languages = mergeLanguage(merged.Languages, language)
+		}
+	}
+	return merged
+}
+
+// mergeJobAds combines the job ads extracted from consecutive chunks like mergeResumes, joining
+// their raw texts.
+func mergeJobAds(parts []model.JobAd) model.JobAd {
+	var merged model.JobAd
+	var rawTexts []string
+	for _, part := range parts {
+		mergeString(&merged.Title, part.Title)
+		mergeString(&merged.CompanyName, part.CompanyName)
+		mergeString(&merged.Location, part.Location)
+		merged.KeyResponsibilities = appendUnique(merged.KeyResponsibilities, part.KeyResponsibilities...)
+		merged.RequiredQualifications = appendUnique(merged.RequiredQualifications, part.RequiredQualifications...)
+		merged.PreferredQualifications = appendUnique(merged.PreferredQualifications, part.PreferredQualifications...)
+		merged.Skills = appendUnique(merged.Skills, part.Skills...)
+		mergeString(&merged.ContractType, part.ContractType)
+		mergeString(&merged.Seniority, part.Seniority)
+		merged.MinYearsOfExperience = max(merged.MinYearsOfExperience, part.MinYearsOfExperience)
+		mergeString(&merged.RemotePolicy, part.RemotePolicy)
+		mergeString(&merged.StartDate, part.StartDate)
+		mergeString(&merged.Duration, part.Duration)
+		if merged.Budget.Min == 0 && merged.Budget.Max == 0 {
+			merged.Budget = part.Budget
+		}
+		for _, language := range part.RequiredLanguages {
+			merged.RequiredLanguages = mergeLanguage(merged.RequiredLanguages, language)
+		}
+		merged.Positions = max(merged.Positions, part.Positions)
+		if text := strings.TrimSpace(part.RawText); text != "" {
+			rawTexts = append(rawTexts, text)
+		}
+	}
+	merged.RawText = strings.Join(rawTexts, "\n\n")
+	return merged
+}
+
+// mergeExperience adds experience to experiences, or completes the one it duplicates. An
+// experience cut by a chunk boundary is recognized by its company and title when one of the
+// halves has no dates.
+func mergeExperience(experiences []model.Experience, experience model.Experience) []model.Experience {
+	for i, existing := range experiences {
+		if normalizeKey(existing.CompanyName) != normalizeKey(experience.CompanyName) ||
+			normalizeKey(existing.JobTitle) != normalizeKey(experience.JobTitle) {
+			continue
+		}
+		sameDates := normalizeKey(existing.Dates) == normalizeKey(experience.Dates)
+		if !sameDates && existing.Dates != "" && experience.Dates != "" {
+			continue
+		}
+		mergeString(&experiences[i].Dates, experience.Dates)
+		experiences[i].Description = mergeText(existing.Description, experience.Description, "\n")
+		experiences[i].Tools = mergeText(existing.Tools, experience.Tools, ", ")
+		return experiences
+	}
+	return append(experiences, experience)
+}
+
+// mergeSkills adds skill to skills, or completes the one with the same name.
+func mergeSkills(skills []model.Skill, skill model.Skill) []model.Skill {
+	key := normalizeKey(skill.Name)
+	if key == "" {
+		return skills
+	}
+	for i, existing := range skills {
+		if normalizeKey(existing.Name) == key {
+			mergeString(&skills[i].Category, skill.Category)
+			mergeString(&skills[i].Level, skill.Level)
+			return skills
+		}
+	}
+	skill.Name = strings.TrimSpace(skill.Name)
+	return append(skills, skill)
+}
+
+// mergeEducation adds education to educations, or completes the one with the same degree and
+// school.
+func mergeEducation(educations []model.Education, education model.Education) []model.Education {
+	for i, existing := range educations {
+		if normalizeKey(existing.Degree) == normalizeKey(education.Degree) &&
+			normalizeKey(existing.School) == normalizeKey(education.School) {
+			mergeString(&educations[i].Field, education.Field)
+			mergeString(&educations[i].Year, education.Year)
+			return educations
+		}
+	}
+	return append(educations, education)
+}
+
+// mergeLanguage adds language to languages, or keeps the highest level of the same language,
+// which chunks may name in different languages.
+func mergeLanguage(languages []model.Language, language model.Language) []model.Language {
+	for i, existing := range languages {
+		if languageKey(existing.Name) != languageKey(language.Name) {
+			continue
+		}
+		if model.CEFRRank(language.Level) > model.CEFRRank(existing.Level) {
+			languages[i].Level = language.Level
+			languages[i].Proficiency = language.Proficiency
+		}
+		mergeString(&languages[i].Proficiency, language.Proficiency)
+		languages[i].Native = existing.Native || language.Native
+		return languages
+	}
+	return append(languages, language)
+}
+
+func mergeString(dst *string, value string) {
+	if strings.TrimSpace(*dst) == "" {
+		*dst = strings.TrimSpace(value)
+	}
+}
+
+// mergeText joins two texts, unless one of them already contains the other.
+func mergeText(a, b, sep string) string {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case b == "" || strings.Contains(normalizeKey(a), normalizeKey(b)):
+		return a
+	case a == "" || strings.Contains(normalizeKey(b), normalizeKey(a)):
+		return b
+	default:
+		return a + sep + b
+	}
+}
+
+// appendUnique appends the values that are not already in list, ignoring case and spacing.
+func appendUnique(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, value := range list {
+		seen[normalizeKey(value)] = true
+	}
+	for _, value := range values {
+		key := normalizeKey(value)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, strings.TrimSpace(value))
+	}
+	return list
+}
+
+func normalizeKey(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
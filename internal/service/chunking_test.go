@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestSplitDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"fits", "Jean Dupont\n\nDéveloppeur Go", 100, []string{"Jean Dupont\n\nDéveloppeur Go"}},
+		{"between paragraphs", "aaaa\n\nbbbb\n\ncccc", 10, []string{"aaaa\n\nbbbb", "cccc"}},
+		{"heading kept with its paragraph", "intro text\n\nEXPERIENCE\n\nAcme 2020", 20,
+			[]string{"intro text", "EXPERIENCE\nAcme 2020"}},
+		{"new section once half full", "a longer introduction\n\nSKILLS:\nGo", 40, []string{"a longer introduction", "SKILLS:\nGo"}},
+		{"no new section before half full", "short intro\n\nSKILLS:\nGo", 40, []string{"short intro\n\nSKILLS:\nGo"}},
+		{"long paragraph cut between lines", "line one\nline two\nline three", 18,
+			[]string{"line one\nline two", "line three"}},
+		{"long line cut between words", "alpha beta gamma delta", 11, []string{"alpha beta", "gamma delta"}},
+		{"long word cut", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitDocument(tt.text, tt.maxChars)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitDocument() = %q, want %q", got, tt.want)
+			}
+			for _, chunk := range got {
+				if utf8.RuneCountInString(chunk) > tt.maxChars {
+					t.Errorf("chunk %q is longer than %d runes", chunk, tt.maxChars)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeResumes(t *testing.T) {
+	merged := mergeResumes([]model.CandidateResume{
+		{
+			FullName: "Jean Dupont",
+			Skills:   []model.Skill{{Name: "Go"}},
+			Experiences: []model.Experience{
+				{CompanyName: "Acme", JobTitle: "Développeur", Dates: "2019 - 2021", Description: "API de paiement"},
+			},
+			Languages: []model.Language{{Name: "Anglais", Level: "B2"}},
+		},
+		{
+			Email:  "jean@example.com",
+			Skills: []model.Skill{{Name: " go ", Category: "Langages"}, {Name: "Kubernetes"}},
+			Experiences: []model.Experience{
+				{CompanyName: "ACME", JobTitle: "développeur", Description: "Migration vers Kubernetes"},
+				{CompanyName: "Globex", JobTitle: "Stagiaire", Dates: "2018"},
+			},
+			Languages: []model.Language{{Name: "English", Level: "C1"}, {Name: "Français", Native: true}},
+		},
+	})
+
+	if merged.FullName != "Jean Dupont" || merged.Email != "jean@example.com" {
+		t.Errorf("merged contact = %q <%s>", merged.FullName, merged.Email)
+	}
+	if want := []model.Skill{{Name: "Go", Category: "Langages"}, {Name: "Kubernetes"}}; !reflect.DeepEqual(merged.Skills, want) {
+		t.Errorf("merged skills = %+v, want %+v", merged.Skills, want)
+	}
+	if len(merged.Experiences) != 2 {
+		t.Fatalf("merged experiences = %+v, want the Acme halves joined", merged.Experiences)
+	}
+	if acme := merged.Experiences[0]; acme.Dates != "2019 - 2021" || acme.Description != "API de paiement\nMigration vers Kubernetes" {
+		t.Errorf("merged Acme experience = %+v", acme)
+	}
+	want := []model.Language{{Name: "Anglais", Level: "C1"}, {Name: "Français", Native: true}}
+	if !slices.Equal(merged.Languages, want) {
+		t.Errorf("merged languages = %+v, want %+v", merged.Languages, want)
+	}
+}
+
+func TestExtractChunked(t *testing.T) {
+	text := "PROFIL\n\nJean Dupont\n\nEXPERIENCE\n\nAcme\n\nFORMATION\n\nINSA"
+	fail := errors.New("provider failure")
+	tests := []struct {
+		name      string
+		extract   func(ctx context.Context, file model.File) (model.CandidateResume, error)
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name: "merged parts",
+			extract: func(ctx context.Context, file model.File) (model.CandidateResume, error) {
+				if file.Part == 1 {
+					return model.CandidateResume{FullName: "Jean Dupont"}, nil
+				}
+				return model.CandidateResume{Skills: []model.Skill{{Name: strings.Fields(file.Text)[1]}}}, nil
+			},
+			wantCalls: 3,
+		},
+		{
+			name: "merged parts without a name",
+			extract: func(ctx context.Context, file model.File) (model.CandidateResume, error) {
+				return model.CandidateResume{}, nil
+			},
+			wantErr:   &LLMError{Kind: LLMErrorInvalidOutput},
+			wantCalls: 3,
+		},
+		{
+			name: "first failure cancels the other parts",
+			extract: func(ctx context.Context, file model.File) (model.CandidateResume, error) {
+				if file.Part == 1 {
+					return model.CandidateResume{}, fail
+				}
+				<-ctx.Done()
+				return model.CandidateResume{}, ctx.Err()
+			},
+			wantErr:   fail,
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSynthesizerService(nil, nil, nil, WithChunking(20, 3))
+			var calls atomic.Int32
+			_, err := extractChunked(context.Background(), s, "test", model.File{Text: text},
+				func(ctx context.Context, file model.File) (model.CandidateResume, error) {
+					calls.Add(1)
+					return tt.extract(ctx, file)
+				}, mergeResumes, model.CheckResume)
+
+			var llmErr *LLMError
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("extractChunked() = %v", err)
+				}
+			case *LLMError:
+				if !errors.As(err, &llmErr) || llmErr.Kind != want.Kind {
+					t.Fatalf("extractChunked() = %v, want a %s error", err, want.Kind)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("extractChunked() = %v, want %v", err, want)
+				}
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("extract was called %d times, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
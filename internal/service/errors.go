@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidArgument is returned when a caller supplied value is rejected.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ErrNotFound is returned when a requested entity does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrProviderUnavailable is returned when a provider is temporarily skipped because it is unhealthy.
+var ErrProviderUnavailable = errors.New("provider is unavailable")
+
+// LLMErrorKind classifies a failure returned by an LLM provider.
+type LLMErrorKind string
+
+const (
+	LLMErrorRetryable       LLMErrorKind = "retryable"
+	LLMErrorRateLimited     LLMErrorKind = "rate_limited"
+	LLMErrorAuth            LLMErrorKind = "auth"
+	LLMErrorInvalidRequest  LLMErrorKind = "invalid_request"
+	LLMErrorContentFiltered LLMErrorKind = "content_filtered"
+	LLMErrorInvalidOutput   LLMErrorKind = "invalid_output"
+)
+
+// LLMError is the typed error returned by providers once a call has definitively failed.
+type LLMError struct {
+	Provider   LLMProviderName
+	Kind       LLMErrorKind
+	StatusCode int
+	RetryAfter time.Duration
+	Attempts   int
+	Err        error
+}
+
+func (e *LLMError) Error() string {
+	msg := fmt.Sprintf("%s provider error (%s", e.Provider, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", status %d", e.StatusCode)
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(", after %d attempts", e.Attempts)
+	}
+	return fmt.Sprintf("%s): %v", msg, e.Err)
+}
+
+func (e *LLMError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the call may succeed if it is attempted again later.
+func (e *LLMError) Retryable() bool {
+	return e.Kind == LLMErrorRetryable || e.Kind == LLMErrorRateLimited
+}
+
+// LLMErrorKindOf returns the kind of the first LLMError found in err's chain, if any.
+func LLMErrorKindOf(err error) (LLMErrorKind, bool) {
+	var llmErr *LLMError
+	if !errors.As(err, &llmErr) {
+		return "", false
+	}
+	return llmErr.Kind, true
+}
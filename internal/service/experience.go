@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/period"
+)
+
+// interval is a range of month indexes, both included.
+type interval struct {
+	first, last int
+}
+
+// enrichResume computes the fields of resume derived from the ones a provider extracted.
+func enrichResume(resume *model.CandidateResume, now time.Time) {
+	u := enrichExperiences(resume, now)
+	resume.SkillExperience = skillExperience(resume, u)
+	enrichSkills(resume, u)
+	resume.Languages = normalizeLanguages(resume.Languages)
+	enrichPreferences(resume, now)
+}
+
+// refreshResume returns a copy of resume enriched at now, so that the durations of current
+// experiences do not go stale once stored. resume itself, whose slices may be shared with the
+// repository, is left untouched.
+func refreshResume(resume model.CandidateResume, now time.Time) model.CandidateResume {
+	resume.Experiences = slices.Clone(resume.Experiences)
+	resume.Skills = slices.Clone(resume.Skills)
+	resume.Languages = slices.Clone(resume.Languages)
+	enrichResume(&resume, now)
+	return resume
+}
+
+// usage finds the dated experiences of a resume that mention a skill or tool.
+type usage struct {
+	texts     []string
+	intervals []*interval
+}
+
+// of returns the intervals of the dated experiences whose title, description or tools mention
+// name.
+func (u usage) of(name string) []interval {
+	needle := " " + normalizeClaim(name) + " "
+	var used []interval
+	for i, text := range u.texts {
+		if u.intervals[i] != nil && strings.Contains(text, needle) {
+			used = append(used, *u.intervals[i])
+		}
+	}
+	return used
+}
+
+// enrichExperiences parses the dates of the experiences of resume, and computes its years of
+// experience. Experiences whose dates cannot be parsed are left undated and do not count.
+func enrichExperiences(resume *model.CandidateResume, now time.Time) usage {
+	u := usage{
+		texts:     make([]string, len(resume.Experiences)),
+		intervals: make([]*interval, len(resume.Experiences)),
+	}
+	var all []interval
+	for i := range resume.Experiences {
+		experience := &resume.Experiences[i]
+		u.texts[i] = " " + normalizeClaim(strings.Join([]string{experience.JobTitle, experience.Description, experience.Tools}, " ")) + " "
+		experience.StartDate, experience.EndDate, experience.Current, experience.DurationMonths = nil, nil, false, 0
+		p, ok := period.Parse(experience.Dates, now)
+		if !ok {
+			continue
+		}
+		start := p.Start
+		experience.StartDate = &start
+		experience.EndDate = p.End
+		experience.Current = p.Current
+		experience.DurationMonths = p.Months(now)
+		first, last := p.Bounds(now)
+		u.intervals[i] = &interval{first, last}
+		all = append(all, interval{first, last})
+	}
+	resume.ExperienceMonths = coveredMonths(all)
+	resume.YearsOfExperience = monthsToYears(resume.ExperienceMonths)
+	return u
+}
+
+// skillExperience computes the months of use of each skill of resume and each tool of its
+// experiences, from the dated experiences that mention them.
+func skillExperience(resume *model.CandidateResume, u usage) []model.SkillExperience {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if key := normalizeClaim(name); key != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	for _, skill := range resume.Skills {
+		add(skill.Name)
+	}
+	for _, experience := range resume.Experiences {
+		for _, tool := range toolSeparators.Split(experience.Tools, -1) {
+			add(tool)
+		}
+	}
+
+	var result []model.SkillExperience
+	for _, name := range names {
+		if months := coveredMonths(u.of(name)); months > 0 {
+			result = append(result, model.SkillExperience{Skill: name, Months: months, Years: monthsToYears(months)})
+		}
+	}
+	slices.SortStableFunc(result, func(a, b model.SkillExperience) int {
+		return cmp.Or(cmp.Compare(b.Months, a.Months), strings.Compare(a.Skill, b.Skill))
+	})
+	return result
+}
+
+// coveredMonths counts the months covered by at least one interval.
+func coveredMonths(intervals []interval) int {
+	slices.SortFunc(intervals, func(a, b interval) int { return cmp.Compare(a.first, b.first) })
+	months := 0
+	end := math.MinInt
+	for _, in := range intervals {
+		first := max(in.first, end+1)
+		if in.last >= first {
+			months += in.last - first + 1
+			end = in.last
+		}
+	}
+	return months
+}
+
+// monthsToYears converts months to years, rounded to one decimal.
+func monthsToYears(months int) float64 {
+	return math.Round(float64(months)/12*10) / 10
+}
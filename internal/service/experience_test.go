@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestRefreshResume(t *testing.T) {
+	stored := model.CandidateResume{
+		Experiences: []model.Experience{{JobTitle: "Développeur Go", Dates: "janvier 2020 - aujourd'hui"}},
+		Skills:      []model.Skill{{Name: "Go"}},
+	}
+	enrichResume(&stored, time.Date(2021, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		wantMonths int
+	}{
+		{name: "when stored", now: time.Date(2021, time.January, 15, 0, 0, 0, 0, time.UTC), wantMonths: 13},
+		{name: "a year later", now: time.Date(2022, time.January, 15, 0, 0, 0, 0, time.UTC), wantMonths: 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := refreshResume(stored, tt.now)
+			if got.Experiences[0].DurationMonths != tt.wantMonths {
+				t.Errorf("DurationMonths = %d, want %d", got.Experiences[0].DurationMonths, tt.wantMonths)
+			}
+			if got.ExperienceMonths != tt.wantMonths {
+				t.Errorf("ExperienceMonths = %d, want %d", got.ExperienceMonths, tt.wantMonths)
+			}
+		})
+	}
+	if stored.Experiences[0].DurationMonths != 13 {
+		t.Errorf("stored DurationMonths = %d, want 13", stored.Experiences[0].DurationMonths)
+	}
+}
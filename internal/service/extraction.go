@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// TextExtractor converts an uploaded document into clean text before it is sent to a provider.
+type TextExtractor interface {
+	Extract(ctx context.Context, file model.File) (model.SourceDocument, error)
+}
+
+// WithTextExtractor extracts the text of every parsed document, which is sent to providers that
+// cannot read the file itself and stored on the parsed entity.
+func WithTextExtractor(extractor TextExtractor) Option {
+	return func(s *SynthesizerService) {
+		s.extractor = extractor
+	}
+}
+
+// extractText fills the MIME type and text of file. Documents that cannot be converted are
+// rejected, while extraction failures are logged so that providers reading files can still be used.
+func (s *SynthesizerService) extractText(ctx context.Context, file model.File) (model.File, *model.SourceDocument, error) {
+	if s.extractor == nil {
+		return file, nil, nil
+	}
+	source, err := s.extractor.Extract(ctx, file)
+	if err != nil {
+		if errors.Is(err, ErrInvalidArgument) {
+			return file, nil, err
+		}
+		s.logger.Warn().Err(err).Str("file", file.Name).Msg("failed to extract document text")
+		return file, nil, nil
+	}
+	file.MimeType = source.MimeType
+	file.Text = source.Text
+	return file, &source, nil
+}
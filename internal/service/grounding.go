@@ -0,0 +1,256 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// GroundingMode selects what happens to the claims of an adapted resume that its source resumes
+// do not support.
+type GroundingMode string
+
+const (
+	// GroundingFlag keeps unsupported claims and reports them as findings.
+	GroundingFlag GroundingMode = "flag"
+	// GroundingStrip removes unsupported skills, certifications, tools and experiences, and
+	// reports them as findings.
+	GroundingStrip GroundingMode = "strip"
+	// GroundingOff disables the check.
+	GroundingOff GroundingMode = "off"
+)
+
+const (
+	findingSkill         = "skill"
+	findingCertification = "certification"
+	findingTool          = "tool"
+	findingEmployer      = "employer"
+	findingDates         = "dates"
+	findingEducation     = "education"
+	findingLanguage      = "language"
+
+	findingFlagged  = "flagged"
+	findingStripped = "stripped"
+)
+
+var (
+	toolSeparators = regexp.MustCompile(`\s*[,;/|]\s*`)
+	yearPattern    = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+)
+
+// WithGrounding sets how adapted resumes are checked against their source resumes.
+func WithGrounding(mode GroundingMode) Option {
+	return func(s *SynthesizerService) {
+		s.grounding = mode
+	}
+}
+
+// evidence is everything the source resumes of an adaptation state about the candidate.
+type evidence struct {
+	text      string
+	employers []string
+	years     map[string][]string
+	// languages maps the key of each language, see languageKey, to its highest CEFR rank.
+	languages map[string]int
+}
+
+func newEvidence(resumes []model.CandidateResume) evidence {
+	e := evidence{years: make(map[string][]string), languages: make(map[string]int)}
+	var parts []string
+	for _, resume := range resumes {
+		parts = append(parts, resume.Description, resume.ShortDescription)
+		parts = append(parts, model.SkillNames(resume.Skills)...)
+		parts = append(parts, resume.Certifications...)
+		for _, experience := range resume.Experiences {
+			parts = append(parts, experience.CompanyName, experience.JobTitle, experience.Description, experience.Tools)
+			employer := normalizeClaim(experience.CompanyName)
+			e.employers = append(e.employers, employer)
+			e.years[employer] = append(e.years[employer], yearPattern.FindAllString(experience.Dates, -1)...)
+		}
+		for _, education := range resume.Education {
+			parts = append(parts, education.Degree, education.School, education.Field, education.Year)
+		}
+		for _, language := range resume.Languages {
+			parts = append(parts, language.Name)
+			name := languageKey(language.Name)
+			e.languages[name] = max(e.languages[name], model.CEFRRank(language.Level))
+		}
+		if resume.Source != nil {
+			parts = append(parts, resume.Source.Text)
+		}
+	}
+	e.text = " " + normalizeClaim(strings.Join(parts, " ")) + " "
+	return e
+}
+
+// supports reports whether claim appears as a whole phrase in the source resumes.
+func (e evidence) supports(claim string) bool {
+	normalized := normalizeClaim(claim)
+	return normalized == "" || strings.Contains(e.text, " "+normalized+" ")
+}
+
+func (e evidence) supportsEmployer(employer string) bool {
+	normalized := normalizeClaim(employer)
+	for _, known := range e.employers {
+		if sameEmployer(known, normalized) {
+			return true
+		}
+	}
+	return e.supports(employer)
+}
+
+// sameEmployer matches company names loosely, so that "Acme" and "Acme SAS" are the same employer.
+func sameEmployer(a, b string) bool {
+	return a != "" && b != "" && (strings.Contains(" "+a+" ", " "+b+" ") || strings.Contains(" "+b+" ", " "+a+" "))
+}
+
+// supportsDates checks that the years of an experience are those of the same employer in the
+// source resumes, or of any source experience when the employer is not found.
+func (e evidence) supportsDates(employer, dates string) bool {
+	normalized := normalizeClaim(employer)
+	var known, all []string
+	for name, years := range e.years {
+		all = append(all, years...)
+		if sameEmployer(name, normalized) {
+			known = append(known, years...)
+		}
+	}
+	if known == nil {
+		known = all
+	}
+	for _, year := range yearPattern.FindAllString(dates, -1) {
+		if !slices.Contains(known, year) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkGrounding verifies every skill, certification, tool, employer, date, education and
+// language of the adapted resume against its sources, records the findings on it and, in strip
+// mode, removes the unsupported claims that can be removed and lowers overstated language levels.
+func (s *SynthesizerService) checkGrounding(adapted *model.CandidateAdaptedResume, sources []model.CandidateResume) {
+	if s.grounding == GroundingOff {
+		return
+	}
+	e := newEvidence(sources)
+	strip := s.grounding == GroundingStrip
+	resume := &adapted.Resume
+	var findings []model.GroundingFinding
+	report := func(field, kind, value string, stripped bool) {
+		action := findingFlagged
+		if stripped {
+			action = findingStripped
+		}
+		findings = append(findings, model.GroundingFinding{Field: field, Kind: kind, Value: value, Action: action})
+	}
+
+	resume.Skills = filterClaims(resume.Skills, skillName, e.supports, strip, func(i int, value string) {
+		report(fmt.Sprintf("skills[%d]", i), findingSkill, value, strip)
+	})
+	resume.Certifications = filterClaims(resume.Certifications, claimText, e.supports, strip, func(i int, value string) {
+		report(fmt.Sprintf("certifications[%d]", i), findingCertification, value, strip)
+	})
+
+	experiences := resume.Experiences[:0]
+	for i, experience := range resume.Experiences {
+		field := fmt.Sprintf("experiences[%d]", i)
+		if !e.supportsEmployer(experience.CompanyName) {
+			report(field+".company_name", findingEmployer, experience.CompanyName, strip)
+			if strip {
+				continue
+			}
+		}
+		if !e.supportsDates(experience.CompanyName, experience.Dates) {
+			report(field+".dates", findingDates, experience.Dates, false)
+		}
+		tools := toolSeparators.Split(strings.TrimSpace(experience.Tools), -1)
+		tools = filterClaims(tools, claimText, e.supports, strip, func(_ int, value string) {
+			report(field+".tools", findingTool, value, strip)
+		})
+		if strip {
+			experience.Tools = strings.Join(tools, ", ")
+		}
+		experiences = append(experiences, experience)
+	}
+	resume.Experiences = experiences
+
+	educations := resume.Education[:0]
+	for i, education := range resume.Education {
+		if !e.supports(education.Degree) && !e.supports(education.School) {
+			report(fmt.Sprintf("education[%d]", i), findingEducation, cmp.Or(education.Degree, education.School), strip)
+			if strip {
+				continue
+			}
+		}
+		educations = append(educations, education)
+	}
+	resume.Education = educations
+
+	languages := resume.Languages[:0]
+	for i, language := range resume.Languages {
+		field := fmt.Sprintf("languages[%d]", i)
+		rank, ok := e.languages[languageKey(language.Name)]
+		if !ok {
+			report(field, findingLanguage, language.Name, strip)
+			if strip {
+				continue
+			}
+		} else if rank > 0 && model.CEFRRank(language.Level) > rank {
+			report(field+".level", findingLanguage, language.Name+" "+language.Level, strip)
+			if strip {
+				language.Level = model.CEFRLevels[rank-1]
+			}
+		}
+		languages = append(languages, language)
+	}
+	resume.Languages = languages
+
+	adapted.GroundingFindings = findings
+	if len(findings) > 0 {
+		adapted.NeedsReview = true
+		adapted.ReviewReasons = append(adapted.ReviewReasons,
+			fmt.Sprintf("%d claims are not supported by the source resumes", len(findings)))
+	}
+}
+
+// filterClaims calls unsupported for each claim whose text supported rejects, and drops those
+// claims when strip is set.
+func filterClaims[T any](claims []T, text func(T) string, supported func(string) bool, strip bool, unsupported func(i int, value string)) []T {
+	kept := make([]T, 0, len(claims))
+	for i, claim := range claims {
+		value := text(claim)
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if !supported(value) {
+			unsupported(i, value)
+			if strip {
+				continue
+			}
+		}
+		kept = append(kept, claim)
+	}
+	return kept
+}
+
+func claimText(claim string) string { return claim }
+
+func skillName(skill model.Skill) string { return skill.Name }
+
+// normalizeClaim lowercases text and keeps only the characters that make up technology names,
+// so that "Node.js," and "node.js" compare equal.
+func normalizeClaim(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#.", r)
+	})
+	for i, field := range fields {
+		fields[i] = strings.Trim(field, ".")
+	}
+	return strings.Join(slices.DeleteFunc(fields, func(field string) bool { return field == "" }), " ")
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+const (
+	suspiciousHiddenText  = "hidden_text"
+	suspiciousInstruction = "instruction"
+
+	// excerptRunes is the context kept on each side of a suspicious instruction.
+	excerptRunes = 60
+)
+
+// instructionPattern is text that addresses a model rather than a human reader.
+type instructionPattern struct {
+	pattern *regexp.Regexp
+	reason  string
+}
+
+var instructionPatterns = []instructionPattern{
+	{
+		regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\b[^.\n]{0,40}\b(?:instructions?|prompts?|rules|guidelines|directions)\b`),
+		"asks to ignore instructions",
+	},
+	{
+		regexp.MustCompile(`(?i)\b(?:ignore[rz]?|oublie[rz]?|ne\s+(?:tiens|tenez)\s+pas\s+compte)\b[^.\n]{0,40}\b(?:instructions?|consignes?|r[èe]gles|directives)\b`),
+		"asks to ignore instructions",
+	},
+	{
+		regexp.MustCompile(`(?i)\b(?:you\s+are\s+now|from\s+now\s+on\s+you|act\s+as\s+(?:an?\s+)?(?:ai|assistant|recruiter|model))\b`),
+		"tries to change the role of the model",
+	},
+	{
+		regexp.MustCompile(`(?i)\b(?:tu\s+es|vous\s+[êe]tes)\s+(?:d[ée]sormais|maintenant)\b|\bagis\s+comme\b|\bagissez\s+comme\b`),
+		"tries to change the role of the model",
+	},
+	{
+		regexp.MustCompile(`(?i)system\s+prompt|<\|im_(?:start|end)\|>|<\|system\|>|\[/?INST\]|###\s*(?:instruction|system)|</?(?:document|job_ad|resume)>`),
+		"contains prompt markup",
+	},
+	{
+		regexp.MustCompile(`(?i)\b(?:rate|score|rank|evaluate|consider|mark)\b[^.\n]{0,40}\b(?:perfect|ideal|best|top|excellent|strong)\s+(?:match|candidate|fit)\b`),
+		"tries to influence the evaluation",
+	},
+	{
+		regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(?:[ée]valuer?|[ée]valuez|noter?|notez|consid[ée]rer?|consid[ée]rez|classer?|classez)\b[^.\n]{0,40}\b(?:parfait|id[ée]al|meilleur)e?\b`),
+		"tries to influence the evaluation",
+	},
+	{
+		regexp.MustCompile(`(?i)\b(?:note|message|instructions?)\s+(?:to|for|à|pour)\s+(?:the\s+|l['’]\s*)?(?:ai|ia|llm|assistant|model|mod[èe]le|chatbot)\b`),
+		"addresses the model",
+	},
+}
+
+// inspectSource reports the hidden text of a document, and the passages of its text and hidden
+// text that address instructions to a model.
+func inspectSource(source *model.SourceDocument) []model.SuspiciousContent {
+	if source == nil {
+		return nil
+	}
+	var suspicious []model.SuspiciousContent
+	for _, hidden := range source.HiddenText {
+		reason := hidden.Reason
+		if hidden.Page > 0 {
+			reason = fmt.Sprintf("%s on page %d", reason, hidden.Page)
+		}
+		suspicious = append(suspicious, model.SuspiciousContent{Kind: suspiciousHiddenText, Text: hidden.Text, Reason: reason})
+	}
+
+	texts := []string{source.Text}
+	for _, hidden := range source.HiddenText {
+		texts = append(texts, hidden.Text)
+	}
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, p := range instructionPatterns {
+			for _, match := range p.pattern.FindAllStringIndex(text, -1) {
+				excerpt := excerpt(text, match[0], match[1])
+				if seen[excerpt] {
+					continue
+				}
+				seen[excerpt] = true
+				suspicious = append(suspicious, model.SuspiciousContent{Kind: suspiciousInstruction, Text: excerpt, Reason: p.reason})
+			}
+		}
+	}
+	return suspicious
+}
+
+// excerpt returns the bytes [start, end) of text with some context, on a single line.
+func excerpt(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+	from := max(len(before)-excerptRunes, 0)
+	to := min(excerptRunes, len(after))
+	return strings.Join(strings.Fields(string(before[from:])+text[start:end]+string(after[:to])), " ")
+}
+
+// reviewReason describes why an entity used by an adaptation needs a review.
+func reviewReason(entity string, id int, suspicious []model.SuspiciousContent) string {
+	return fmt.Sprintf("%s %d contains %d suspicious passages that may manipulate the model", entity, id, len(suspicious))
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/period"
+)
+
+var (
+	asapPattern     = regexp.MustCompile(`(?i)\b(?:asap|as soon as possible|d[èe]s que possible|imm[ée]diat(?:e|ement)?|immediately)\b`)
+	durationPattern = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(mois|months?|années?|ans?|years?|semaines?|weeks?)`)
+	// noticePattern matches an availability given as a delay, such as "préavis de 3 mois" or
+	// "in 2 months".
+	noticePattern = regexp.MustCompile(`(?i)\b(?:dans|sous|pr[ée]avis|notice|in|within)\b\D{0,15}\d+(?:[.,]\d+)?\s*(?:mois|months?|semaines?|weeks?)`)
+)
+
+// remoteNegationPattern, fullRemotePattern and partialRemotePattern find, in lower case remote
+// policies, the wordings that rule remote work out, that make it the rule, and that limit it to
+// some days or some occasions. remoteMentionPattern and onsiteMentionPattern find any mention of
+// remote and onsite work.
+var (
+	remoteNegationPattern = regexp.MustCompile(`\b(?:pas|sans|aucun|no|not|non)\s+(?:de\s+|du\s+)?(?:t[ée]l[ée]travail|remote)|(?:t[ée]l[ée]travail|remote)\s+(?:non|impossible|exclu|not)\b`)
+	fullRemotePattern     = regexp.MustCompile(`fully?[ -]remote|100\s*%\s*(?:remote|t[ée]l[ée]travail|[àa] distance)|t[ée]l[ée]travail\s+(?:complet|total|int[ée]gral)|remote only|enti[èe]rement [àa] distance`)
+	partialRemotePattern  = regexp.MustCompile(`\b[1-4]\s*(?:jours?|j|days?)\b|\b(?:possible|occasionnel(?:le)?|ponctuel(?:le)?|partiel|partial|flexible|par semaine|per week|a week)\b`)
+	remoteMentionPattern  = regexp.MustCompile(`t[ée]l[ée]travail|remote|[àa] distance`)
+	onsiteMentionPattern  = regexp.MustCompile(`sur site|pr[ée]sentiel|on[- ]?site|sur place|au bureau|in office`)
+)
+
+// seniorityYears are the years of experience each seniority requires.
+var seniorityYears = map[string]float64{
+	model.SeniorityJunior:       0,
+	model.SeniorityIntermediate: 2,
+	model.SenioritySenior:       5,
+	model.SeniorityExpert:       10,
+}
+
+// contractWords, seniorityWords and remoteWords are the French and English words job ads use for
+// contract types, seniorities and remote policies, tried in order. Remote policies are first read
+// with the patterns above, remoteWords only holding the bare keywords.
+var (
+	contractWords = []wordValue{
+		{"portage", model.ContractPortage},
+		{"sous-trait", model.ContractSubcontracting},
+		{"sous trait", model.ContractSubcontracting},
+		{"subcontract", model.ContractSubcontracting},
+		{"freelance", model.ContractFreelance},
+		{"free-lance", model.ContractFreelance},
+		{"indépendant", model.ContractFreelance},
+		{"independant", model.ContractFreelance},
+		{"contractor", model.ContractFreelance},
+		{"cdd", model.ContractFixedTerm},
+		{"fixed", model.ContractFixedTerm},
+		{"durée déterminée", model.ContractFixedTerm},
+		{"cdi", model.ContractPermanent},
+		{"permanent", model.ContractPermanent},
+		{"durée indéterminée", model.ContractPermanent},
+	}
+	seniorityWords = []wordValue{
+		{"expert", model.SeniorityExpert},
+		{"lead", model.SeniorityExpert},
+		{"principal", model.SeniorityExpert},
+		{"senior", model.SenioritySenior},
+		{"sénior", model.SenioritySenior},
+		{"confirm", model.SeniorityIntermediate},
+		{"intermediate", model.SeniorityIntermediate},
+		{"intermédiaire", model.SeniorityIntermediate},
+		{"mid", model.SeniorityIntermediate},
+		{"junior", model.SeniorityJunior},
+		{"débutant", model.SeniorityJunior},
+		{"entry", model.SeniorityJunior},
+	}
+	remoteWords = []wordValue{
+		{"hybrid", model.RemoteHybrid},
+		{"hybride", model.RemoteHybrid},
+		{"partiel", model.RemoteHybrid},
+		{"partial", model.RemoteHybrid},
+		{"sur site", model.RemoteOnsite},
+		{"présentiel", model.RemoteOnsite},
+		{"on site", model.RemoteOnsite},
+		{"on-site", model.RemoteOnsite},
+		{"onsite", model.RemoteOnsite},
+		{"sur place", model.RemoteOnsite},
+		{"remote", model.RemoteFull},
+		{"télétravail", model.RemoteFull},
+		{"teletravail", model.RemoteFull},
+		{"à distance", model.RemoteFull},
+	}
+)
+
+// compatibleRemotePolicies are the remote policies of the job ads each remote preference of a
+// candidate accepts.
+var compatibleRemotePolicies = map[string][]string{
+	model.RemoteOnsite: {model.RemoteOnsite, model.RemoteHybrid},
+	model.RemoteHybrid: {model.RemoteHybrid, model.RemoteFull},
+	model.RemoteFull:   {model.RemoteFull},
+}
+
+// compatibleBillingModes are the billing modes of the consultants each contract type can take.
+// Subcontracting missions take any consultant, and are left out.
+var compatibleBillingModes = map[string][]string{
+	model.ContractFreelance: {model.BillingFreelance, model.BillingPortage},
+	model.ContractPortage:   {model.BillingPortage, model.BillingFreelance},
+	model.ContractPermanent: {model.BillingSalaried},
+	model.ContractFixedTerm: {model.BillingSalaried},
+}
+
+// normalizeJobAd normalizes the mission fields of jobAd as parsed by a provider, and computes
+// the fields derived from them.
+func (s *SynthesizerService) normalizeJobAd(jobAd *model.JobAd, now time.Time) {
+	jobAd.ContractType = normalizeEnum(jobAd.ContractType, model.ContractTypes, contractWords)
+	jobAd.Seniority = normalizeEnum(jobAd.Seniority, model.Seniorities, seniorityWords)
+	jobAd.RemotePolicy = normalizeRemotePolicy(jobAd.RemotePolicy)
+	jobAd.MinYearsOfExperience = max(jobAd.MinYearsOfExperience, 0)
+	jobAd.Positions = max(jobAd.Positions, 1)
+	jobAd.RequiredLanguages = normalizeLanguages(jobAd.RequiredLanguages)
+
+	jobAd.StartASAP, jobAd.StartMonth = false, nil
+	if asapPattern.MatchString(jobAd.StartDate) {
+		jobAd.StartASAP = true
+	} else if p, ok := period.Parse(jobAd.StartDate, now); ok {
+		start := p.Start
+		jobAd.StartMonth = &start
+	}
+	jobAd.DurationMonths = durationMonths(jobAd.Duration)
+
+	jobAd.Budget = s.normalizeBudget(jobAd.Budget)
+	jobAd.DailyBudget = nil
+	minimum, okMin := s.currencies.Convert(model.Rate{Amount: jobAd.Budget.Min, Currency: jobAd.Budget.Currency, Unit: jobAd.Budget.Unit}, s.currencies.Base, model.RateDay)
+	maximum, okMax := s.currencies.Convert(model.Rate{Amount: jobAd.Budget.Max, Currency: jobAd.Budget.Currency, Unit: jobAd.Budget.Unit}, s.currencies.Base, model.RateDay)
+	if okMin || okMax {
+		jobAd.DailyBudget = &model.RateRange{Min: minimum.Amount, Max: maximum.Amount, Currency: s.currencies.Base, Unit: model.RateDay}
+	}
+}
+
+// enrichPreferences normalizes the remote preference of resume, and computes the fields derived
+// from its availability and mission duration.
+func enrichPreferences(resume *model.CandidateResume, now time.Time) {
+	resume.RemotePreference = normalizeRemotePolicy(resume.RemotePreference)
+	resume.AvailableASAP, resume.AvailableFrom = false, nil
+	switch {
+	case asapPattern.MatchString(resume.Availability):
+		resume.AvailableASAP = true
+	case noticePattern.MatchString(resume.Availability):
+		// A notice period runs from now, as the candidate only resigns once hired.
+		months := durationMonths(noticePattern.FindString(resume.Availability))
+		from := model.YearMonth{Year: now.Year(), Month: int(now.Month())}
+		index := firstMonth(from) + months
+		from.Year, from.Month = index/12, index%12+1
+		resume.AvailableFrom = &from
+	default:
+		if p, ok := period.Parse(resume.Availability, now); ok {
+			start := p.Start
+			resume.AvailableFrom = &start
+		}
+	}
+	resume.MinMissionMonths = durationMonths(resume.MissionDuration)
+}
+
+// normalizeRemotePolicy returns policy when it is one of the remote policies, or else reads it
+// from its wording: negations such as "pas de télétravail" and mixed wordings such as
+// "présentiel 3 jours, 2 jours de télétravail" are read before the bare keywords.
+func normalizeRemotePolicy(policy string) string {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if policy == "" || slices.Contains(model.RemotePolicies, policy) {
+		return policy
+	}
+	rest := remoteNegationPattern.ReplaceAllString(policy, " ")
+	remote := remoteMentionPattern.MatchString(rest)
+	switch {
+	case fullRemotePattern.MatchString(rest):
+		return model.RemoteFull
+	case remote && (onsiteMentionPattern.MatchString(rest) || partialRemotePattern.MatchString(rest)):
+		return model.RemoteHybrid
+	case rest != policy && !remote:
+		return model.RemoteOnsite
+	}
+	return normalizeEnum(rest, model.RemotePolicies, remoteWords)
+}
+
+// normalizeBudget normalizes a budget like a rate, and orders its bounds.
+func (s *SynthesizerService) normalizeBudget(budget model.RateRange) model.RateRange {
+	budget.Min, budget.Max = max(budget.Min, 0), max(budget.Max, 0)
+	if budget.Min == 0 && budget.Max == 0 {
+		return model.RateRange{}
+	}
+	if budget.Max > 0 && budget.Min > budget.Max {
+		budget.Min, budget.Max = budget.Max, budget.Min
+	}
+	rate := s.normalizeRate(model.Rate{Amount: max(budget.Min, budget.Max), Currency: budget.Currency, Unit: budget.Unit})
+	budget.Currency, budget.Unit = rate.Currency, rate.Unit
+	return budget
+}
+
+// wordValue is a word found in free text and the value it stands for.
+type wordValue struct {
+	word  string
+	value string
+}
+
+// normalizeEnum returns value when it is one of values, or else the value of the first word it
+// contains, or else an empty string.
+func normalizeEnum(value string, values []string, words []wordValue) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || slices.Contains(values, value) {
+		return value
+	}
+	for _, w := range words {
+		if strings.Contains(value, w.word) {
+			return w.value
+		}
+	}
+	return ""
+}
+
+// durationMonths reads a duration such as "6 mois renouvelables" or "1 year" in months, or
+// returns 0.
+func durationMonths(duration string) int {
+	match := durationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	unit := strings.ToLower(match[2])
+	switch {
+	case strings.HasPrefix(unit, "an") || strings.HasPrefix(unit, "year"):
+		value *= 12
+	case strings.HasPrefix(unit, "sem") || strings.HasPrefix(unit, "week"):
+		value = value * 12 / 52
+	}
+	return int(math.Ceil(value))
+}
+
+// JobAdFilter selects the job ads of a contract type, seniority and remote policy, requiring a
+// language, whose daily budget can pay DailyRate and which start by StartBy. An empty filter
+// selects every job ad.
+type JobAdFilter struct {
+	ContractType string
+	Seniority    string
+	RemotePolicy string
+	Language     string
+	DailyRate    float64
+	StartBy      *model.YearMonth
+}
+
+// Matches reports whether jobAd is selected by f.
+func (f JobAdFilter) Matches(jobAd model.JobAd) bool {
+	if (f.ContractType != "" && jobAd.ContractType != f.ContractType) ||
+		(f.Seniority != "" && jobAd.Seniority != f.Seniority) ||
+		(f.RemotePolicy != "" && jobAd.RemotePolicy != f.RemotePolicy) {
+		return false
+	}
+	if f.Language != "" && !slices.ContainsFunc(jobAd.RequiredLanguages, func(language model.Language) bool {
+		return languageKey(language.Name) == languageKey(f.Language)
+	}) {
+		return false
+	}
+	if f.DailyRate > 0 && (jobAd.DailyBudget == nil || max(jobAd.DailyBudget.Min, jobAd.DailyBudget.Max) < f.DailyRate) {
+		return false
+	}
+	if f.StartBy != nil && !jobAd.StartASAP && (jobAd.StartMonth == nil || firstMonth(*jobAd.StartMonth) > lastMonth(*f.StartBy)) {
+		return false
+	}
+	return true
+}
+
+// firstMonth and lastMonth return the index of the first and last months of ym, a date without
+// month covering the whole year.
+func firstMonth(ym model.YearMonth) int {
+	return ym.Year*12 + max(ym.Month, 1) - 1
+}
+
+func lastMonth(ym model.YearMonth) int {
+	if ym.Month == 0 {
+		return ym.Year*12 + 11
+	}
+	return ym.Year*12 + ym.Month - 1
+}
+
+// MatchResumes matches every resume against the job ad jobAdID, those meeting its requirements
+// first, then those having the most of its skills.
+func (s *SynthesizerService) MatchResumes(ctx context.Context, jobAdID int) ([]model.JobMatch, error) {
+	jobAd, err := s.repository.GetJobAd(ctx, jobAdID)
+	if err != nil {
+		return nil, err
+	}
+	resumes, err := s.repository.ListResumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	matches := make([]model.JobMatch, len(resumes))
+	for i, resume := range resumes {
+		matches[i] = matchResume(jobAd, refreshResume(resume, now), now)
+	}
+	slices.SortStableFunc(matches, func(a, b model.JobMatch) int {
+		return cmp.Or(cmp.Compare(len(a.Mismatches), len(b.Mismatches)),
+			cmp.Compare(len(b.MatchedSkills), len(a.MatchedSkills)))
+	})
+	return matches, nil
+}
+
+// matchResume compares resume with the skills and mission requirements of jobAd at now.
+// Requirements the resume does not tell about are not reported as mismatches.
+func matchResume(jobAd model.JobAd, resume model.CandidateResume, now time.Time) model.JobMatch {
+	match := model.JobMatch{ResumeID: resume.ID, CandidateID: resume.CandidateID, FullName: resume.FullName}
+	for _, skill := range jobAd.Skills {
+		if (SkillFilter{Name: skill}).Matches(resume) {
+			match.MatchedSkills = append(match.MatchedSkills, skill)
+		} else {
+			match.MissingSkills = append(match.MissingSkills, skill)
+		}
+	}
+
+	if modes, ok := compatibleBillingModes[jobAd.ContractType]; ok && resume.BillingMode != "" && !slices.Contains(modes, resume.BillingMode) {
+		match.Mismatches = append(match.Mismatches,
+			fmt.Sprintf("billing mode %s does not suit a %s contract", resume.BillingMode, jobAd.ContractType))
+	}
+	if jobAd.DailyBudget != nil && jobAd.DailyBudget.Max > 0 && resume.DailyRate != nil && resume.DailyRate.Amount > jobAd.DailyBudget.Max {
+		match.Mismatches = append(match.Mismatches, fmt.Sprintf("daily rate of %s %s exceeds the budget of %s %s",
+			formatAmount(resume.DailyRate.Amount), resume.DailyRate.Currency, formatAmount(jobAd.DailyBudget.Max), jobAd.DailyBudget.Currency))
+	}
+	minYears := max(jobAd.MinYearsOfExperience, seniorityYears[jobAd.Seniority])
+	if minYears > 0 && resume.ExperienceMonths > 0 && resume.YearsOfExperience < minYears {
+		match.Mismatches = append(match.Mismatches, fmt.Sprintf("%s years of experience instead of at least %s",
+			formatAmount(resume.YearsOfExperience), formatAmount(minYears)))
+	}
+	if policies, ok := compatibleRemotePolicies[resume.RemotePreference]; ok && jobAd.RemotePolicy != "" && !slices.Contains(policies, jobAd.RemotePolicy) {
+		match.Mismatches = append(match.Mismatches,
+			fmt.Sprintf("wants %s work but the mission is %s", resume.RemotePreference, jobAd.RemotePolicy))
+	}
+	if resume.AvailableFrom != nil && !resume.AvailableASAP {
+		available := firstMonth(*resume.AvailableFrom)
+		switch {
+		case jobAd.StartASAP && available > firstMonth(model.YearMonth{Year: now.Year(), Month: int(now.Month())}):
+			match.Mismatches = append(match.Mismatches, fmt.Sprintf("available from %s but the mission starts as soon as possible",
+				*resume.AvailableFrom))
+		case jobAd.StartMonth != nil && available > lastMonth(*jobAd.StartMonth):
+			match.Mismatches = append(match.Mismatches, fmt.Sprintf("available from %s but the mission starts in %s",
+				*resume.AvailableFrom, *jobAd.StartMonth))
+		}
+	}
+	if jobAd.DurationMonths > 0 && resume.MinMissionMonths > jobAd.DurationMonths {
+		match.Mismatches = append(match.Mismatches, fmt.Sprintf("mission of %d months shorter than the %d months sought",
+			jobAd.DurationMonths, resume.MinMissionMonths))
+	}
+	if len(resume.Languages) > 0 {
+		for _, required := range jobAd.RequiredLanguages {
+			i := slices.IndexFunc(resume.Languages, func(language model.Language) bool {
+				return languageKey(language.Name) == languageKey(required.Name)
+			})
+			switch {
+			case i < 0:
+				match.Mismatches = append(match.Mismatches, fmt.Sprintf("does not speak %s", required.Name))
+			case model.CEFRRank(resume.Languages[i].Level) > 0 && model.CEFRRank(resume.Languages[i].Level) < model.CEFRRank(required.Level):
+				match.Mismatches = append(match.Mismatches, fmt.Sprintf("%s level %s below the required %s",
+					required.Name, resume.Languages[i].Level, required.Level))
+			}
+		}
+	}
+	return match
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestNormalizeEnum(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		values []string
+		words  []wordValue
+		want   string
+	}{
+		{"known value", " Freelance ", model.ContractTypes, contractWords, model.ContractFreelance},
+		{"word", "Portage salarial", model.ContractTypes, contractWords, model.ContractPortage},
+		{"first word in order", "CDI ou freelance", model.ContractTypes, contractWords, model.ContractFreelance},
+		{"French seniority", "Profil confirmé", model.Seniorities, seniorityWords, model.SeniorityIntermediate},
+		{"unknown", "stage", model.ContractTypes, contractWords, ""},
+		{"empty", "", model.Seniorities, seniorityWords, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeEnum(tt.value, tt.values, tt.words); got != tt.want {
+				t.Errorf("normalizeEnum(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRemotePolicy(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"Hybrid", model.RemoteHybrid},
+		{"Full remote", model.RemoteFull},
+		{"100% télétravail", model.RemoteFull},
+		{"Télétravail", model.RemoteFull},
+		{"Sur site", model.RemoteOnsite},
+		{"présentiel 3 jours, 2 jours de télétravail", model.RemoteHybrid},
+		{"2 jours de remote par semaine", model.RemoteHybrid},
+		{"télétravail possible", model.RemoteHybrid},
+		{"télétravail partiel", model.RemoteHybrid},
+		{"pas de remote", model.RemoteOnsite},
+		{"Pas de télétravail", model.RemoteOnsite},
+		{"sans télétravail, présentiel", model.RemoteOnsite},
+		{"remote not possible", model.RemoteOnsite},
+		{"100% remote possible", model.RemoteFull},
+		{"à définir", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			if got := normalizeRemotePolicy(tt.policy); got != tt.want {
+				t.Errorf("normalizeRemotePolicy(%q) = %q, want %q", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationMonths(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     int
+	}{
+		{"6 mois renouvelables", 6},
+		{"1 year", 12},
+		{"2 ans", 24},
+		{"1,5 an", 18},
+		{"12 weeks", 3},
+		{"3 semaines", 1},
+		{"long terme", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			if got := durationMonths(tt.duration); got != tt.want {
+				t.Errorf("durationMonths(%q) = %d, want %d", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrichPreferences(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		availability string
+		wantASAP     bool
+		wantFrom     *model.YearMonth
+	}{
+		{name: "immediately", availability: "Disponible immédiatement", wantASAP: true},
+		{name: "month", availability: "Disponible en mars 2027", wantFrom: &model.YearMonth{Year: 2027, Month: 3}},
+		{name: "notice", availability: "Préavis de 3 mois", wantFrom: &model.YearMonth{Year: 2027, Month: 1}},
+		{name: "unknown", availability: "à discuter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume := model.CandidateResume{Availability: tt.availability, RemotePreference: "pas de remote", MissionDuration: "6 mois minimum"}
+			enrichPreferences(&resume, now)
+			if resume.AvailableASAP != tt.wantASAP {
+				t.Errorf("AvailableASAP = %v, want %v", resume.AvailableASAP, tt.wantASAP)
+			}
+			if (resume.AvailableFrom == nil) != (tt.wantFrom == nil) || (tt.wantFrom != nil && *resume.AvailableFrom != *tt.wantFrom) {
+				t.Errorf("AvailableFrom = %v, want %v", resume.AvailableFrom, tt.wantFrom)
+			}
+			if resume.RemotePreference != model.RemoteOnsite || resume.MinMissionMonths != 6 {
+				t.Errorf("RemotePreference, MinMissionMonths = %q, %d, want %q, 6",
+					resume.RemotePreference, resume.MinMissionMonths, model.RemoteOnsite)
+			}
+		})
+	}
+}
+
+func TestJobAdFilterMatches(t *testing.T) {
+	jobAd := model.JobAd{
+		ContractType:      model.ContractFreelance,
+		Seniority:         model.SenioritySenior,
+		RemotePolicy:      model.RemoteHybrid,
+		RequiredLanguages: []model.Language{{Name: "Anglais", Level: "B2"}},
+		DailyBudget:       &model.RateRange{Min: 500, Max: 650, Currency: "EUR", Unit: model.RateDay},
+		StartMonth:        &model.YearMonth{Year: 2026, Month: 11},
+	}
+	tests := []struct {
+		name   string
+		filter JobAdFilter
+		jobAd  model.JobAd
+		want   bool
+	}{
+		{name: "empty filter", jobAd: model.JobAd{}, want: true},
+		{name: "all criteria", filter: JobAdFilter{ContractType: model.ContractFreelance, Seniority: model.SenioritySenior,
+			RemotePolicy: model.RemoteHybrid, Language: "english", DailyRate: 600, StartBy: &model.YearMonth{Year: 2026, Month: 12}}, jobAd: jobAd, want: true},
+		{name: "other contract type", filter: JobAdFilter{ContractType: model.ContractPermanent}, jobAd: jobAd},
+		{name: "other remote policy", filter: JobAdFilter{RemotePolicy: model.RemoteFull}, jobAd: jobAd},
+		{name: "language not required", filter: JobAdFilter{Language: "Allemand"}, jobAd: jobAd},
+		{name: "rate above the budget", filter: JobAdFilter{DailyRate: 700}, jobAd: jobAd},
+		{name: "rate without budget", filter: JobAdFilter{DailyRate: 500}, jobAd: model.JobAd{}},
+		{name: "starts later", filter: JobAdFilter{StartBy: &model.YearMonth{Year: 2026, Month: 10}}, jobAd: jobAd},
+		{name: "starts within the year", filter: JobAdFilter{StartBy: &model.YearMonth{Year: 2026}}, jobAd: jobAd, want: true},
+		{name: "starts as soon as possible", filter: JobAdFilter{StartBy: &model.YearMonth{Year: 2026, Month: 1}}, jobAd: model.JobAd{StartASAP: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.jobAd); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchResume(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	jobAd := model.JobAd{
+		Skills:            []string{"Go", "Kubernetes"},
+		ContractType:      model.ContractFreelance,
+		RemotePolicy:      model.RemoteOnsite,
+		RequiredLanguages: []model.Language{{Name: "Anglais", Level: "C1"}},
+		DailyBudget:       &model.RateRange{Max: 600, Currency: "EUR", Unit: model.RateDay},
+		StartMonth:        &model.YearMonth{Year: 2026, Month: 11},
+		DurationMonths:    3,
+	}
+	tests := []struct {
+		name           string
+		jobAd          model.JobAd
+		resume         model.CandidateResume
+		wantMissing    []string
+		wantMismatches []string
+	}{
+		{
+			name:        "resume telling nothing",
+			jobAd:       jobAd,
+			resume:      model.CandidateResume{Skills: []model.Skill{{Name: "Go"}, {Name: "Kubernetes"}}},
+			wantMissing: nil,
+		},
+		{
+			name:  "every mismatch",
+			jobAd: jobAd,
+			resume: model.CandidateResume{
+				Skills:           []model.Skill{{Name: "Go"}},
+				BillingMode:      model.BillingSalaried,
+				DailyRate:        &model.Rate{Amount: 700, Currency: "EUR", Unit: model.RateDay},
+				RemotePreference: model.RemoteFull,
+				AvailableFrom:    &model.YearMonth{Year: 2027, Month: 1},
+				MinMissionMonths: 6,
+				Languages:        []model.Language{{Name: "Anglais", Level: "B1"}},
+			},
+			wantMissing: []string{"Kubernetes"},
+			wantMismatches: []string{
+				"billing mode salaried does not suit a freelance contract",
+				"daily rate of 700 EUR exceeds the budget of 600 EUR",
+				"wants remote work but the mission is onsite",
+				"available from 2027-01 but the mission starts in 2026-11",
+				"mission of 3 months shorter than the 6 months sought",
+				"Anglais level B1 below the required C1",
+			},
+		},
+		{
+			name:  "resume without languages",
+			jobAd: jobAd,
+			resume: model.CandidateResume{
+				Skills:           []model.Skill{{Name: "Go"}, {Name: "Kubernetes"}},
+				RemotePreference: model.RemoteHybrid,
+				MinMissionMonths: 12,
+			},
+			wantMismatches: []string{
+				"wants hybrid work but the mission is onsite",
+				"mission of 3 months shorter than the 12 months sought",
+			},
+		},
+		{
+			name:  "not available as soon as possible",
+			jobAd: model.JobAd{StartASAP: true, RemotePolicy: model.RemoteHybrid},
+			resume: model.CandidateResume{
+				RemotePreference: model.RemoteOnsite,
+				AvailableFrom:    &model.YearMonth{Year: 2026, Month: 12},
+				Languages:        []model.Language{{Name: "Français", Level: "C2"}},
+			},
+			wantMismatches: []string{"available from 2026-12 but the mission starts as soon as possible"},
+		},
+		{
+			name:  "available in time",
+			jobAd: jobAd,
+			resume: model.CandidateResume{
+				Skills:           []model.Skill{{Name: "Go"}, {Name: "Kubernetes"}},
+				RemotePreference: model.RemoteOnsite,
+				AvailableFrom:    &model.YearMonth{Year: 2026, Month: 11},
+				Languages:        []model.Language{{Name: "Anglais", Level: "C2"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchResume(tt.jobAd, tt.resume, now)
+			if !slices.Equal(got.MissingSkills, tt.wantMissing) {
+				t.Errorf("MissingSkills = %v, want %v", got.MissingSkills, tt.wantMissing)
+			}
+			if !slices.Equal(got.Mismatches, tt.wantMismatches) {
+				t.Errorf("Mismatches = %q, want %q", got.Mismatches, tt.wantMismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+var (
+	cefrPattern   = regexp.MustCompile(`(?i)\b([abc][12])\b`)
+	toeicPattern  = regexp.MustCompile(`(?i)\btoeic\D{0,10}(\d{3})\b`)
+	nativePattern = regexp.MustCompile(`(?i)\b(?:native|natif|maternelle|mother tongue)\b`)
+)
+
+// proficiencyWords maps the words describing a language proficiency in French and English to
+// CEFR levels. Longer phrases come first, so that "très bon" is not read as "bon".
+var proficiencyWords = []struct {
+	words []string
+	level string
+}{
+	{[]string{"langue maternelle", "mother tongue", "maternelle", "native", "natif", "bilingual", "bilingue"}, model.LevelC2},
+	{[]string{"full professional", "courant", "fluent", "fluently", "advanced", "avancé", "très bon", "very good"}, model.LevelC1},
+	{[]string{"professional working", "professionnel", "professional", "opérationnel", "operational", "upper intermediate"}, model.LevelB2},
+	{[]string{"intermédiaire", "intermediate", "conversational", "conversationnel", "bon", "good"}, model.LevelB1},
+	{[]string{"notions", "basique", "basic", "scolaire", "elementary", "élémentaire", "limited"}, model.LevelA2},
+	{[]string{"débutant", "beginner"}, model.LevelA1},
+}
+
+// toeicLevels are the minimum TOEIC scores of each CEFR level, from the highest.
+var toeicLevels = []struct {
+	score int
+	level string
+}{
+	{945, model.LevelC1},
+	{785, model.LevelB2},
+	{550, model.LevelB1},
+	{225, model.LevelA2},
+	{120, model.LevelA1},
+}
+
+// normalizeLanguages drops the languages without a name, and sets the CEFR level of the others
+// from their proficiency when the model gave none or an invalid one. Mother tongues are C2.
+func normalizeLanguages(languages []model.Language) []model.Language {
+	normalized := languages[:0]
+	for _, language := range languages {
+		language.Name = strings.TrimSpace(language.Name)
+		if language.Name == "" {
+			continue
+		}
+		language.Native = language.Native || nativePattern.MatchString(language.Proficiency)
+		switch {
+		case language.Native:
+			language.Level = model.LevelC2
+		case model.CEFRRank(language.Level) > 0:
+			language.Level = strings.ToUpper(strings.TrimSpace(language.Level))
+		default:
+			language.Level = proficiencyLevel(language.Proficiency)
+		}
+		normalized = append(normalized, language)
+	}
+	return normalized
+}
+
+// proficiencyLevel reads the CEFR level of a proficiency stated as a CEFR level, a TOEIC score or
+// in words, or returns an empty string.
+func proficiencyLevel(proficiency string) string {
+	if match := cefrPattern.FindStringSubmatch(proficiency); match != nil {
+		return strings.ToUpper(match[1])
+	}
+	if match := toeicPattern.FindStringSubmatch(proficiency); match != nil {
+		score, _ := strconv.Atoi(match[1])
+		for _, l := range toeicLevels {
+			if score >= l.score {
+				return l.level
+			}
+		}
+		return ""
+	}
+	text := " " + normalizeClaim(proficiency) + " "
+	for _, p := range proficiencyWords {
+		for _, word := range p.words {
+			if strings.Contains(text, " "+word+" ") {
+				return p.level
+			}
+		}
+	}
+	return ""
+}
+
+// languageNames maps the French names of common languages to the English ones, so that a job ad
+// and resumes written in different languages can be compared.
+var languageNames = map[string]string{
+	"anglais": "english", "français": "french", "francais": "french", "allemand": "german",
+	"espagnol": "spanish", "italien": "italian", "portugais": "portuguese", "néerlandais": "dutch",
+	"neerlandais": "dutch", "arabe": "arabic", "chinois": "chinese", "mandarin": "chinese",
+	"japonais": "japanese", "russe": "russian", "polonais": "polish",
+}
+
+// languageKey returns the English name of a language, whatever language it is written in.
+func languageKey(name string) string {
+	key := normalizeClaim(name)
+	return cmp.Or(languageNames[key], key)
+}
@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale, such as "fr", prompts should use.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale carried by ctx, or an empty string.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
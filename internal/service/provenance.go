@@ -0,0 +1,321 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+const (
+	// exactMatchConfidence and foldedMatchConfidence rate values found verbatim, or found after
+	// ignoring case and spacing.
+	exactMatchConfidence  = 1.0
+	foldedMatchConfidence = 0.9
+	// maxPartialConfidence rates values whose words are all found, but not together.
+	maxPartialConfidence = 0.8
+	// snippetRunes is the context shown on each side of a located value.
+	snippetRunes = 40
+)
+
+// fieldValue is a parsed field and its value, the field being a JSON path such as
+// "experiences[0].company_name".
+type fieldValue struct {
+	field string
+	value string
+}
+
+func resumeFields(resume model.CandidateResume) []fieldValue {
+	fields := []fieldValue{
+		{"full_name", resume.FullName},
+		{"email", resume.Email},
+		{"phone", resume.Phone},
+		{"description", resume.Description},
+		{"short_description", resume.ShortDescription},
+		{"location", resume.Location},
+		{"availability", resume.Availability},
+		{"mission_duration", resume.MissionDuration},
+		{"rate.amount", formatAmount(resume.Rate.Amount)},
+	}
+	for i, experience := range resume.Experiences {
+		path := fmt.Sprintf("experiences[%d]", i)
+		fields = append(fields,
+			fieldValue{path + ".company_name", experience.CompanyName},
+			fieldValue{path + ".dates", experience.Dates},
+			fieldValue{path + ".job_title", experience.JobTitle},
+			fieldValue{path + ".description", experience.Description},
+			fieldValue{path + ".tools", experience.Tools},
+		)
+	}
+	for i, education := range resume.Education {
+		path := fmt.Sprintf("education[%d]", i)
+		fields = append(fields,
+			fieldValue{path + ".degree", education.Degree},
+			fieldValue{path + ".school", education.School},
+			fieldValue{path + ".field", education.Field},
+			fieldValue{path + ".year", education.Year},
+		)
+	}
+	for i, language := range resume.Languages {
+		path := fmt.Sprintf("languages[%d]", i)
+		fields = append(fields,
+			fieldValue{path + ".name", language.Name},
+			fieldValue{path + ".proficiency", language.Proficiency},
+		)
+	}
+	fields = appendListFields(fields, "certifications", resume.Certifications)
+	return appendListFields(fields, "skills", model.SkillNames(resume.Skills))
+}
+
+// formatAmount formats an amount as written in documents, or returns an empty string for a zero
+// amount.
+func formatAmount(amount float64) string {
+	if amount == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+func jobAdFields(jobAd model.JobAd) []fieldValue {
+	fields := []fieldValue{
+		{"title", jobAd.Title},
+		{"company_name", jobAd.CompanyName},
+		{"location", jobAd.Location},
+		{"start_date", jobAd.StartDate},
+		{"duration", jobAd.Duration},
+		{"budget.min", formatAmount(jobAd.Budget.Min)},
+		{"budget.max", formatAmount(jobAd.Budget.Max)},
+	}
+	fields = appendListFields(fields, "key_responsibilities", jobAd.KeyResponsibilities)
+	fields = appendListFields(fields, "required_qualifications", jobAd.RequiredQualifications)
+	fields = appendListFields(fields, "preferred_qualifications", jobAd.PreferredQualifications)
+	fields = appendListFields(fields, "skills", jobAd.Skills)
+	for i, language := range jobAd.RequiredLanguages {
+		fields = append(fields, fieldValue{fmt.Sprintf("required_languages[%d].name", i), language.Name})
+	}
+	return fields
+}
+
+func appendListFields(fields []fieldValue, name string, values []string) []fieldValue {
+	for i, value := range values {
+		fields = append(fields, fieldValue{fmt.Sprintf("%s[%d]", name, i), value})
+	}
+	return fields
+}
+
+// spellings returns the values of a skill or tool field as the provider extracted them, before the
+// taxonomy renamed their terms.
+func (r renamedTerms) spellings(field fieldValue) []string {
+	if len(r) == 0 {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(field.field, "skills["):
+		return r[field.value]
+	case strings.HasSuffix(field.field, ".tools"):
+		terms := strings.Split(field.value, ", ")
+		renamed := false
+		for i, term := range terms {
+			if originals := r[term]; len(originals) > 0 {
+				terms[i], renamed = originals[0], true
+			}
+		}
+		if renamed {
+			return []string{strings.Join(terms, ", ")}
+		}
+	}
+	return nil
+}
+
+// locateFields finds where each non-empty field value appears in the source text, or else where
+// one of the spellings the taxonomy renamed appears.
+func locateFields(fields []fieldValue, renamed renamedTerms, source *model.SourceDocument) []model.FieldProvenance {
+	if source == nil || strings.TrimSpace(source.Text) == "" {
+		return nil
+	}
+	folded := foldText(source.Text)
+	normalized := " " + normalizeClaim(source.Text) + " "
+	var provenance []model.FieldProvenance
+	for _, field := range fields {
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			continue
+		}
+		p := model.FieldProvenance{Field: field.field, Value: value}
+		start, end, confidence := locate(source.Text, folded, normalized, value)
+		for _, spelling := range renamed.spellings(field) {
+			if confidence == exactMatchConfidence {
+				break
+			}
+			if s, e, c := locate(source.Text, folded, normalized, spelling); c > confidence {
+				start, end, confidence = s, e, c
+			}
+		}
+		p.Confidence = confidence
+		if p.Confidence > 0 {
+			p.Span = &model.TextSpan{Start: start, End: end}
+			p.Page = pageAt(source.PageOffsets, start)
+			p.Snippet = snippet(source.Text, start, end)
+			p.Confidence *= pageQuality(source.OCRPages, p.Page)
+		}
+		p.Confidence = math.Round(p.Confidence*100) / 100
+		provenance = append(provenance, p)
+	}
+	return provenance
+}
+
+// locate returns the character range of value in text and how confidently it was found: verbatim,
+// ignoring case and spacing, or else word by word.
+func locate(text string, folded foldedText, normalized, value string) (int, int, float64) {
+	if i := indexWord(text, value); i >= 0 {
+		start := utf8.RuneCountInString(text[:i])
+		return start, start + utf8.RuneCountInString(value), exactMatchConfidence
+	}
+	if start, end, ok := folded.find(value); ok {
+		return start, end, foldedMatchConfidence
+	}
+	return bestLine(text, normalized, value)
+}
+
+// foldedText is a text lowercased with its whitespace runs collapsed, offsets giving, for each
+// byte of the folded text, the character offset it comes from in the original text.
+type foldedText struct {
+	text    string
+	offsets []int
+}
+
+func foldText(text string) foldedText {
+	var b strings.Builder
+	var offsets []int
+	space := false
+	offset := 0
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+		} else {
+			if space {
+				b.WriteByte(' ')
+				offsets = append(offsets, offset)
+				space = false
+			}
+			lower := string(unicode.ToLower(r))
+			b.WriteString(lower)
+			for range len(lower) {
+				offsets = append(offsets, offset)
+			}
+		}
+		offset++
+	}
+	return foldedText{text: b.String(), offsets: offsets}
+}
+
+// find returns the character range of the original text where value appears, ignoring case and
+// spacing.
+func (f foldedText) find(value string) (int, int, bool) {
+	needle := foldText(value).text
+	i := indexWord(f.text, needle)
+	if needle == "" || i < 0 {
+		return 0, 0, false
+	}
+	return f.offsets[i], f.offsets[i+len(needle)-1] + 1, true
+}
+
+// indexWord returns the byte index of the first occurrence of needle in text that is not part of a
+// longer word, so that "Go" is not found in "Google", or -1.
+func indexWord(text, needle string) int {
+	for offset := 0; needle != ""; {
+		i := strings.Index(text[offset:], needle)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[i+len(needle):])
+		if !isWordRune(before) && !isWordRune(after) {
+			return i
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		offset = i + size
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// bestLine returns the line of text sharing the most words with value, rated by the share of
+// the words of value found anywhere in the text.
+func bestLine(text, normalizedText, value string) (int, int, float64) {
+	words := strings.Fields(normalizeClaim(value))
+	found := 0
+	for _, word := range words {
+		if strings.Contains(normalizedText, " "+word+" ") {
+			found++
+		}
+	}
+	if found == 0 {
+		return 0, 0, 0
+	}
+
+	bestStart, bestEnd, bestScore := 0, 0, 0
+	offset := 0
+	for _, line := range strings.Split(text, "\n") {
+		lineRunes := utf8.RuneCountInString(line)
+		normalizedLine := " " + normalizeClaim(line) + " "
+		score := 0
+		for _, word := range words {
+			if strings.Contains(normalizedLine, " "+word+" ") {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestStart, bestEnd, bestScore = offset, offset+lineRunes, score
+		}
+		offset += lineRunes + 1
+	}
+	return bestStart, bestEnd, maxPartialConfidence * float64(found) / float64(len(words))
+}
+
+// pageAt returns the 1-based page holding the character at offset, or 0 when pages are unknown.
+func pageAt(pageOffsets []int, offset int) int {
+	page := 0
+	for i, start := range pageOffsets {
+		if start > offset {
+			break
+		}
+		page = i + 1
+	}
+	return page
+}
+
+// pageQuality scales confidences by the OCR confidence of page, when the page was OCRed.
+func pageQuality(pages []model.OCRPage, page int) float64 {
+	for _, p := range pages {
+		if p.Page == page {
+			return p.Confidence / 100
+		}
+	}
+	return 1
+}
+
+func snippet(text string, start, end int) string {
+	runes := []rune(text)
+	from, to := max(start-snippetRunes, 0), min(end+snippetRunes, len(runes))
+	return strings.Join(strings.Fields(string(runes[from:to])), " ")
+}
+
+// LowConfidenceFields returns the fields whose confidence is below threshold.
+func LowConfidenceFields(provenance []model.FieldProvenance, threshold float64) []model.FieldProvenance {
+	var fields []model.FieldProvenance
+	for _, p := range provenance {
+		if p.Confidence < threshold {
+			fields = append(fields, p)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/pii"
+)
+
+// WithPseudonymization keeps personal data away from the given providers: names, emails, phone
+// numbers, addresses and birth dates are replaced with placeholders in what they are sent, and
+// restored in what they return.
+func WithPseudonymization(providers ...LLMProviderName) Option {
+	return func(s *SynthesizerService) {
+		s.pseudonymized = make(map[LLMProviderName]bool, len(providers))
+		for _, name := range providers {
+			s.pseudonymized[name] = true
+		}
+	}
+}
+
+// pseudonymizedDocument calls extract with file, or, when provider must not see personal data,
+// with the redacted text of file only, restoring the placeholders in the result. Documents whose
+// text could not be extracted cannot be redacted, and are rejected.
+func pseudonymizedDocument[T any](
+	s *SynthesizerService,
+	provider LLMProviderName,
+	file model.File,
+	extract func(file model.File) (T, error),
+) (T, error) {
+	if !s.pseudonymized[provider] {
+		return extract(file)
+	}
+	var zero T
+	if file.Text == "" {
+		return zero, fmt.Errorf("%w: the text of %s could not be extracted to remove personal data before sending it to %s",
+			ErrInvalidArgument, file.Name, provider)
+	}
+	p := pii.New()
+	p.DetectNames(file.Text)
+	file.Text = p.Redact(file.Text)
+	file.Content = []byte(file.Text)
+	file.MimeType = "text/plain"
+	result, err := extract(file)
+	if err != nil {
+		return zero, err
+	}
+	p.RestoreValue(&result)
+	return result, nil
+}
+
+// pseudonymizedAdaptation calls adapt with copies of resumes and jobAd whose personal data is
+// replaced with placeholders when provider must not see it, restoring them in the adaptation.
+func pseudonymizedAdaptation(
+	s *SynthesizerService,
+	provider LLMProviderName,
+	jobAd model.JobAd,
+	resumes []model.CandidateResume,
+	adapt func(jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error),
+) (model.ResumeAdaptation, error) {
+	if !s.pseudonymized[provider] {
+		return adapt(jobAd, resumes)
+	}
+	var adaptation model.ResumeAdaptation
+	// Copies are redacted, as the slices of the stored entities are shared with the repository.
+	redactedJobAd, err := deepCopy(jobAd)
+	if err != nil {
+		return adaptation, err
+	}
+	redactedResumes, err := deepCopy(resumes)
+	if err != nil {
+		return adaptation, err
+	}
+
+	p := pii.New()
+	for i := range redactedResumes {
+		resume := &redactedResumes[i]
+		resume.Source = nil
+		resume.Provenance = nil
+		resume.Suspicious = nil
+		p.AddName(resume.FullName)
+	}
+	redactedJobAd.Source = nil
+	redactedJobAd.Provenance = nil
+	redactedJobAd.Suspicious = nil
+	p.RedactValue(&redactedResumes)
+	p.RedactValue(&redactedJobAd)
+
+	adaptation, err = adapt(redactedJobAd, redactedResumes)
+	if err != nil {
+		return adaptation, err
+	}
+	p.RestoreValue(&adaptation)
+	return adaptation, nil
+}
+
+func deepCopy[T any](value T) (T, error) {
+	var out T
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return out, fmt.Errorf("failed to copy %T: %w", value, err)
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return out, fmt.Errorf("failed to copy %T: %w", value, err)
+	}
+	return out, nil
+}
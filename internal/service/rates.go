@@ -0,0 +1,183 @@
+package service
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+const (
+	defaultHoursPerDay  = 8
+	defaultDaysPerMonth = 20
+)
+
+// CurrencyTable converts rates between currencies and units. Rates maps currency codes to the
+// value of one unit of the currency in Base, Base itself being worth 1. Zero HoursPerDay and
+// DaysPerMonth default to 8 and 20.
+type CurrencyTable struct {
+	Base         string
+	Rates        map[string]float64
+	HoursPerDay  float64
+	DaysPerMonth float64
+}
+
+// WithCurrencies converts the rates of resumes to daily rates in the base currency of table.
+// Without it, only rates in the currency of a resume's own rate can be compared.
+func WithCurrencies(table CurrencyTable) Option {
+	return func(s *SynthesizerService) {
+		s.currencies = table
+	}
+}
+
+// Convert converts rate to currency and unit. It reports false when the rate is unknown, or
+// when its currency or unit cannot be converted.
+func (t CurrencyTable) Convert(rate model.Rate, currency, unit string) (model.Rate, bool) {
+	from, okFrom := t.value(rate.Currency)
+	to, okTo := t.value(currency)
+	perDay, okPerDay := t.daysPer(rate.Unit)
+	targetPerDay, okTarget := t.daysPer(unit)
+	if rate.Amount <= 0 || !okFrom || !okTo || !okPerDay || !okTarget {
+		return model.Rate{}, false
+	}
+	amount := rate.Amount / perDay * from / to * targetPerDay
+	return model.Rate{Amount: math.Round(amount*100) / 100, Currency: currency, Unit: unit}, true
+}
+
+// value returns the value of one unit of currency in the base currency.
+func (t CurrencyTable) value(currency string) (float64, bool) {
+	if currency == "" {
+		return 0, false
+	}
+	if currency == t.Base {
+		return 1, true
+	}
+	value, ok := t.Rates[currency]
+	return value, ok && value > 0
+}
+
+// daysPer returns how many days one unit lasts.
+func (t CurrencyTable) daysPer(unit string) (float64, bool) {
+	switch unit {
+	case model.RateDay:
+		return 1, true
+	case model.RateHour:
+		return 1 / cmp.Or(t.HoursPerDay, defaultHoursPerDay), true
+	case model.RateMonth:
+		return cmp.Or(t.DaysPerMonth, defaultDaysPerMonth), true
+	default:
+		return 0, false
+	}
+}
+
+// currencyWords maps the symbols and names resumes use for currencies to their codes.
+var currencyWords = map[string]string{
+	"€": "EUR", "euro": "EUR", "euros": "EUR",
+	"$": "USD", "us$": "USD", "dollar": "USD", "dollars": "USD",
+	"£": "GBP", "pound": "GBP", "pounds": "GBP", "livre": "GBP", "livres": "GBP",
+	"fr": "CHF", "franc suisse": "CHF", "francs suisses": "CHF", "swiss franc": "CHF", "swiss francs": "CHF",
+}
+
+// unitWords maps the French and English words resumes use for rate units to the units.
+var unitWords = map[string]string{
+	"d": model.RateDay, "j": model.RateDay, "jour": model.RateDay, "jours": model.RateDay, "daily": model.RateDay,
+	"per day": model.RateDay, "par jour": model.RateDay, "tjm": model.RateDay, "jh": model.RateDay, "jour homme": model.RateDay,
+	"h": model.RateHour, "heure": model.RateHour, "hourly": model.RateHour, "per hour": model.RateHour, "de l'heure": model.RateHour,
+	"m": model.RateMonth, "mois": model.RateMonth, "mensuel": model.RateMonth, "monthly": model.RateMonth, "per month": model.RateMonth, "par mois": model.RateMonth,
+}
+
+// billingWords are the French and English words resumes use for billing modes, tried in order.
+var billingWords = []wordValue{
+	{"portage", model.BillingPortage},
+	{"umbrella", model.BillingPortage},
+	{"sous-trait", model.BillingSubcontracting},
+	{"sous trait", model.BillingSubcontracting},
+	{"subcontract", model.BillingSubcontracting},
+	{"freelance", model.BillingFreelance},
+	{"free-lance", model.BillingFreelance},
+	{"indépendant", model.BillingFreelance},
+	{"independant", model.BillingFreelance},
+	{"independent", model.BillingFreelance},
+	{"self-employed", model.BillingFreelance},
+	{"contractor", model.BillingFreelance},
+	{"auto-entrepreneur", model.BillingFreelance},
+	{"micro-entreprise", model.BillingFreelance},
+	{"sasu", model.BillingFreelance},
+	{"eurl", model.BillingFreelance},
+	{"salari", model.BillingSalaried},
+	{"cdi", model.BillingSalaried},
+	{"cdd", model.BillingSalaried},
+	{"employee", model.BillingSalaried},
+	{"permanent", model.BillingSalaried},
+}
+
+// normalizeResumeRate normalizes the rate and billing mode of resume as parsed by a provider,
+// and converts the rate to a daily rate in the base currency.
+func (s *SynthesizerService) normalizeResumeRate(resume *model.CandidateResume) {
+	resume.Rate = s.normalizeRate(resume.Rate)
+	resume.BillingMode = normalizeBillingMode(resume.BillingMode)
+	resume.DailyRate = s.dailyRate(resume.Rate)
+}
+
+// dailyRate converts rate to a daily rate in the base currency, or returns nil when it cannot.
+func (s *SynthesizerService) dailyRate(rate model.Rate) *model.Rate {
+	daily, ok := s.currencies.Convert(rate, s.currencies.Base, model.RateDay)
+	if !ok {
+		return nil
+	}
+	return &daily
+}
+
+// normalizeRate turns the currency and unit of rate into a code and a unit. A rate without a
+// currency is taken to be in the base currency, and a rate without a unit to be a daily rate as
+// is usual for consultants. An unknown currency is kept as stated, so that the rate is not
+// converted.
+func (s *SynthesizerService) normalizeRate(rate model.Rate) model.Rate {
+	if rate.Amount <= 0 {
+		return model.Rate{}
+	}
+	currency := strings.TrimSpace(rate.Currency)
+	rate.Currency = cmp.Or(normalizeCurrency(currency), currency, s.currencies.Base)
+	rate.Unit = cmp.Or(normalizeUnit(rate.Unit), model.RateDay)
+	return rate
+}
+
+func normalizeCurrency(currency string) string {
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	if code, ok := currencyWords[currency]; ok {
+		return code
+	}
+	if code := strings.ToUpper(currency); model.IsCurrencyCode(code) {
+		return code
+	}
+	return ""
+}
+
+func normalizeUnit(unit string) string {
+	unit = strings.Trim(strings.ToLower(strings.TrimSpace(unit)), "/")
+	if slices.Contains(model.RateUnits, unit) {
+		return unit
+	}
+	return unitWords[strings.TrimSpace(unit)]
+}
+
+func normalizeBillingMode(mode string) string {
+	return normalizeEnum(mode, model.BillingModes, billingWords)
+}
+
+// RateFilter selects the resumes whose daily rate in the base currency is at most MaxDailyRate,
+// and whose billing mode is BillingMode. An empty filter selects every resume.
+type RateFilter struct {
+	MaxDailyRate float64
+	BillingMode  string
+}
+
+// Matches reports whether resume is selected by f.
+func (f RateFilter) Matches(resume model.CandidateResume) bool {
+	if f.MaxDailyRate > 0 && (resume.DailyRate == nil || resume.DailyRate.Amount > f.MaxDailyRate) {
+		return false
+	}
+	return f.BillingMode == "" || resume.BillingMode == f.BillingMode
+}
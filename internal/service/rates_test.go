@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestCurrencyTableConvert(t *testing.T) {
+	table := CurrencyTable{Base: "EUR", Rates: map[string]float64{"USD": 0.9, "CHF": 1.05, "GBP": 0}}
+	tests := []struct {
+		name     string
+		rate     model.Rate
+		currency string
+		unit     string
+		want     model.Rate
+		wantOK   bool
+	}{
+		{
+			name:     "same currency and unit",
+			rate:     model.Rate{Amount: 600, Currency: "EUR", Unit: model.RateDay},
+			currency: "EUR", unit: model.RateDay,
+			want:   model.Rate{Amount: 600, Currency: "EUR", Unit: model.RateDay},
+			wantOK: true,
+		},
+		{
+			name:     "to the base currency",
+			rate:     model.Rate{Amount: 700, Currency: "USD", Unit: model.RateDay},
+			currency: "EUR", unit: model.RateDay,
+			want:   model.Rate{Amount: 630, Currency: "EUR", Unit: model.RateDay},
+			wantOK: true,
+		},
+		{
+			name:     "between two other currencies",
+			rate:     model.Rate{Amount: 700, Currency: "USD", Unit: model.RateDay},
+			currency: "CHF", unit: model.RateDay,
+			want:   model.Rate{Amount: 600, Currency: "CHF", Unit: model.RateDay},
+			wantOK: true,
+		},
+		{
+			name:     "hourly to daily",
+			rate:     model.Rate{Amount: 75, Currency: "EUR", Unit: model.RateHour},
+			currency: "EUR", unit: model.RateDay,
+			want:   model.Rate{Amount: 600, Currency: "EUR", Unit: model.RateDay},
+			wantOK: true,
+		},
+		{
+			name:     "monthly to daily",
+			rate:     model.Rate{Amount: 12000, Currency: "EUR", Unit: model.RateMonth},
+			currency: "EUR", unit: model.RateDay,
+			want:   model.Rate{Amount: 600, Currency: "EUR", Unit: model.RateDay},
+			wantOK: true,
+		},
+		{
+			name:     "currency without a rate",
+			rate:     model.Rate{Amount: 500, Currency: "JPY", Unit: model.RateDay},
+			currency: "EUR", unit: model.RateDay,
+		},
+		{
+			name:     "currency with a zero rate",
+			rate:     model.Rate{Amount: 500, Currency: "GBP", Unit: model.RateDay},
+			currency: "EUR", unit: model.RateDay,
+		},
+		{
+			name:     "unknown unit",
+			rate:     model.Rate{Amount: 500, Currency: "EUR", Unit: "week"},
+			currency: "EUR", unit: model.RateDay,
+		},
+		{
+			name:     "no amount",
+			rate:     model.Rate{Currency: "EUR", Unit: model.RateDay},
+			currency: "EUR", unit: model.RateDay,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Convert(tt.rate, tt.currency, tt.unit)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Convert() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeRate(t *testing.T) {
+	s := NewSynthesizerService(nil, nil, nil, WithCurrencies(CurrencyTable{Base: "EUR"}))
+	tests := []struct {
+		name string
+		rate model.Rate
+		want model.Rate
+	}{
+		{
+			name: "symbol and French unit",
+			rate: model.Rate{Amount: 550, Currency: "€", Unit: "jour"},
+			want: model.Rate{Amount: 550, Currency: "EUR", Unit: model.RateDay},
+		},
+		{
+			name: "lower case code and hourly",
+			rate: model.Rate{Amount: 80, Currency: " usd ", Unit: "/h"},
+			want: model.Rate{Amount: 80, Currency: "USD", Unit: model.RateHour},
+		},
+		{
+			name: "no currency nor unit",
+			rate: model.Rate{Amount: 600},
+			want: model.Rate{Amount: 600, Currency: "EUR", Unit: model.RateDay},
+		},
+		{
+			name: "not an ISO 4217 code",
+			rate: model.Rate{Amount: 600, Currency: "ABC", Unit: "mois"},
+			want: model.Rate{Amount: 600, Currency: "ABC", Unit: model.RateMonth},
+		},
+		{
+			name: "no amount",
+			rate: model.Rate{Currency: "EUR", Unit: model.RateDay},
+			want: model.Rate{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.normalizeRate(tt.rate); got != tt.want {
+				t.Errorf("normalizeRate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{"EUR", "EUR"},
+		{"chf", "CHF"},
+		{"£", "GBP"},
+		{"dollars", "USD"},
+		{"ABC", ""},
+		{"XAU", ""},
+		{"EURO2", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got := normalizeCurrency(tt.currency); got != tt.want {
+				t.Errorf("normalizeCurrency(%q) = %q, want %q", tt.currency, got, tt.want)
+			}
+		})
+	}
+}
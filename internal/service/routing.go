@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// Operation identifies a kind of LLM call made by the service.
+type Operation string
+
+const (
+	OperationParseResume Operation = "parse_resume"
+	OperationParseJobAd  Operation = "parse_job_ad"
+	OperationAdaptResume Operation = "adapt_resume"
+)
+
+// Operations lists every operation that can be routed to a provider.
+var Operations = []Operation{OperationParseResume, OperationParseJobAd, OperationAdaptResume}
+
+// ProviderRoute is the ordered list of providers tried for an operation. The first one is the
+// default provider, the following ones are fallbacks tried when the previous one fails.
+type ProviderRoute struct {
+	Providers []LLMProviderName
+	// Timeout bounds each provider attempt. Zero means the caller's deadline only.
+	Timeout time.Duration
+}
+
+// Routing maps each operation to its provider route.
+type Routing map[Operation]ProviderRoute
+
+// chain returns the providers to try for an operation. An explicit provider name overrides the
+// configured route and disables fallbacks.
+func (r Routing) chain(op Operation, providerName LLMProviderName) ([]LLMProviderName, error) {
+	if providerName != "" {
+		return []LLMProviderName{providerName}, nil
+	}
+	route, ok := r[op]
+	if !ok || len(route.Providers) == 0 {
+		return nil, fmt.Errorf("no provider given and no default provider configured for %s", op)
+	}
+	return route.Providers, nil
+}
+
+// callWithFallback runs call against each provider of the chain until one succeeds, and returns
+// the result along with the name of the provider that produced it. The usage reported by every
+// attempt, including failed ones, is returned even when all providers fail.
+func callWithFallback[T any](
+	ctx context.Context,
+	s *SynthesizerService,
+	op Operation,
+	providerName LLMProviderName,
+	call func(ctx context.Context, name LLMProviderName, provider LLMProvider) (T, error),
+) (T, LLMProviderName, []model.UsageRecord, error) {
+	var zero T
+	chain, err := s.routing.chain(op, providerName)
+	if err != nil {
+		return zero, "", nil, err
+	}
+
+	var errs []error
+	var usage []model.UsageRecord
+	for _, name := range chain {
+		if err := s.checkBudgets(ctx, BudgetScopeProvider, string(name)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		provider, err := s.llmFactory.GetProvider(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not get llm provider %s: %w", name, err))
+			continue
+		}
+
+		result, attemptUsage, err := callProvider(ctx, s.routing[op].Timeout, name, provider, call)
+		for _, record := range attemptUsage {
+			record.CreatedAt = time.Now()
+			record.Provider = string(name)
+			record.Operation = string(op)
+			usage = append(usage, record)
+		}
+		if err == nil {
+			return result, name, usage, nil
+		}
+		errs = append(errs, fmt.Errorf("llm provider %s: %w", name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return zero, "", usage, fmt.Errorf("%s failed on every provider: %w", op, errors.Join(errs...))
+}
+
+func callProvider[T any](
+	ctx context.Context,
+	timeout time.Duration,
+	name LLMProviderName,
+	provider LLMProvider,
+	call func(ctx context.Context, name LLMProviderName, provider LLMProvider) (T, error),
+) (T, []model.UsageRecord, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ctx, meter := withUsageMeter(ctx)
+	result, err := call(ctx, name, provider)
+	return result, meter.collect(), err
+}
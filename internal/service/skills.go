@@ -0,0 +1,144 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+// inferredLevels are the minimum years of use of each inferred skill level, from the highest.
+var inferredLevels = []struct {
+	years float64
+	level string
+}{
+	{6, model.SkillExpert},
+	{3, model.SkillAdvanced},
+	{1, model.SkillIntermediate},
+	{0, model.SkillBeginner},
+}
+
+// levelWords maps the French and English words resumes use for skill levels to the levels.
+var levelWords = map[string]string{
+	"beginner": model.SkillBeginner, "novice": model.SkillBeginner, "débutant": model.SkillBeginner, "notions": model.SkillBeginner,
+	"intermediate": model.SkillIntermediate, "intermédiaire": model.SkillIntermediate, "junior": model.SkillIntermediate,
+	"advanced": model.SkillAdvanced, "avancé": model.SkillAdvanced, "confirmé": model.SkillAdvanced, "proficient": model.SkillAdvanced,
+	"expert": model.SkillExpert, "expertise": model.SkillExpert, "senior": model.SkillExpert,
+}
+
+// enrichSkills cleans the skills of resume and completes them from its experiences: the years
+// each skill was used, when it was last used, and its level when the resume does not state it.
+func enrichSkills(resume *model.CandidateResume, u usage) {
+	skills := make([]model.Skill, 0, len(resume.Skills))
+	seen := make(map[string]int)
+	for _, skill := range resume.Skills {
+		skill.Name = strings.TrimSpace(skill.Name)
+		key := normalizeClaim(skill.Name)
+		if key == "" {
+			continue
+		}
+		skill.Category = normalizeCategory(skill.Category)
+		if skill.LevelSource == model.LevelInferred {
+			// An inferred level is recomputed below, and must not pass for one the resume states.
+			skill.Level = ""
+		}
+		skill.Level = levelWords[strings.ToLower(strings.TrimSpace(skill.Level))]
+		if i, ok := seen[key]; ok {
+			mergeSkill(&skills[i], skill)
+			continue
+		}
+		seen[key] = len(skills)
+		skills = append(skills, skill)
+	}
+
+	for i := range skills {
+		skill := &skills[i]
+		used := u.of(skill.Name)
+		skill.YearsUsed = monthsToYears(coveredMonths(used))
+		skill.LastUsed = nil
+		if len(used) > 0 {
+			last := slices.MaxFunc(used, func(a, b interval) int { return a.last - b.last }).last
+			skill.LastUsed = &model.YearMonth{Year: last / 12, Month: last%12 + 1}
+		}
+		switch {
+		case skill.Level != "":
+			skill.LevelSource = model.LevelSelf
+		case len(used) > 0:
+			skill.Level = inferLevel(skill.YearsUsed)
+			skill.LevelSource = model.LevelInferred
+		default:
+			skill.LevelSource = ""
+		}
+	}
+	resume.Skills = skills
+}
+
+// mergeSkill completes skill with the category and level of a duplicate.
+func mergeSkill(skill *model.Skill, duplicate model.Skill) {
+	if skill.Category == model.SkillOther {
+		skill.Category = duplicate.Category
+	}
+	mergeString(&skill.Level, duplicate.Level)
+}
+
+func normalizeCategory(category string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if slices.Contains(model.SkillCategories, category) {
+		return category
+	}
+	return model.SkillOther
+}
+
+func inferLevel(years float64) string {
+	for _, l := range inferredLevels {
+		if years >= l.years {
+			return l.level
+		}
+	}
+	return model.SkillBeginner
+}
+
+// emphasizeSkills orders the skills of an adapted resume by relevance to jobAd: first those
+// the job ad requires, then those it prefers, the longest used first within each group.
+func emphasizeSkills(resume *model.CandidateResume, jobAd model.JobAd) {
+	required := " " + normalizeClaim(strings.Join(slices.Concat(jobAd.RequiredQualifications, []string{jobAd.Title}), " ")) + " "
+	preferred := " " + normalizeClaim(strings.Join(slices.Concat(jobAd.PreferredQualifications, jobAd.KeyResponsibilities), " ")) + " "
+	relevance := func(skill model.Skill) int {
+		name := " " + normalizeClaim(skill.Name) + " "
+		switch {
+		case strings.Contains(required, name):
+			return 0
+		case strings.Contains(preferred, name):
+			return 1
+		default:
+			return 2
+		}
+	}
+	slices.SortStableFunc(resume.Skills, func(a, b model.Skill) int {
+		return cmp.Or(cmp.Compare(relevance(a), relevance(b)), cmp.Compare(b.YearsUsed, a.YearsUsed))
+	})
+}
+
+// SkillFilter selects the resumes having a skill, or a skill belonging to it, optionally of a
+// category and used for a minimum number of years. An empty filter selects every resume.
+type SkillFilter struct {
+	Name     string
+	Category string
+	MinYears float64
+}
+
+// Matches reports whether resume has a skill selected by f.
+func (f SkillFilter) Matches(resume model.CandidateResume) bool {
+	if f == (SkillFilter{}) {
+		return true
+	}
+	name := normalizeClaim(f.Name)
+	return slices.ContainsFunc(resume.Skills, func(skill model.Skill) bool {
+		return (name == "" || normalizeClaim(skill.Name) == name || slices.ContainsFunc(skill.Parents, func(parent string) bool {
+			return normalizeClaim(parent) == name
+		})) &&
+			(f.Category == "" || skill.Category == f.Category) &&
+			skill.YearsUsed >= f.MinYears
+	})
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+func TestEnrichSkillsTwice(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		skill           model.Skill
+		experiences     []model.Experience
+		laterExperience []model.Experience
+		wantLevel       string
+		wantSource      string
+	}{
+		{
+			name:        "inferred level stays inferred",
+			skill:       model.Skill{Name: "Go"},
+			experiences: []model.Experience{{JobTitle: "Développeur Go", Dates: "2016 - 2023"}},
+			wantLevel:   model.SkillExpert,
+			wantSource:  model.LevelInferred,
+		},
+		{
+			name:            "inferred level follows the experiences",
+			skill:           model.Skill{Name: "Go"},
+			experiences:     []model.Experience{{JobTitle: "Développeur Go", Dates: "2016 - 2023"}},
+			laterExperience: []model.Experience{{JobTitle: "Développeur Go", Dates: "2022 - 2023"}},
+			wantLevel:       model.SkillIntermediate,
+			wantSource:      model.LevelInferred,
+		},
+		{
+			name:            "inferred level dropped without experience",
+			skill:           model.Skill{Name: "Go"},
+			experiences:     []model.Experience{{JobTitle: "Développeur Go", Dates: "2016 - 2023"}},
+			laterExperience: []model.Experience{{JobTitle: "Chef de projet", Dates: "2016 - 2023"}},
+			wantLevel:       "",
+			wantSource:      "",
+		},
+		{
+			name:        "stated level stays stated",
+			skill:       model.Skill{Name: "Go", Level: "Confirmé"},
+			experiences: []model.Experience{{JobTitle: "Développeur Go", Dates: "2016 - 2023"}},
+			wantLevel:   model.SkillAdvanced,
+			wantSource:  model.LevelSelf,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume := model.CandidateResume{Skills: []model.Skill{tt.skill}, Experiences: tt.experiences}
+			enrichResume(&resume, now)
+			if tt.laterExperience != nil {
+				resume.Experiences = tt.laterExperience
+			}
+			enrichResume(&resume, now)
+
+			skill := resume.Skills[0]
+			if skill.Level != tt.wantLevel || skill.LevelSource != tt.wantSource {
+				t.Errorf("after two runs, level = %q (%q), want %q (%q)", skill.Level, skill.LevelSource, tt.wantLevel, tt.wantSource)
+			}
+		})
+	}
+}
@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/taxonomy"
+	"github.com/rs/zerolog"
 )
 
 type ResumeRepository interface {
@@ -25,71 +29,244 @@ type ResumeRepository interface {
 	ListJobAds(ctx context.Context) ([]model.JobAd, error)
 	UpdateJobAd(ctx context.Context, jobAd model.JobAd) (model.JobAd, error)
 	DeleteJobAd(ctx context.Context, jobAdID int) error
+
+	SaveCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error)
+	GetCandidate(ctx context.Context, candidateID int) (model.Candidate, error)
+	ListCandidates(ctx context.Context) ([]model.Candidate, error)
+	UpdateCandidate(ctx context.Context, candidate model.Candidate) (model.Candidate, error)
+	DeleteCandidate(ctx context.Context, candidateID int) error
 }
 
 type LLMProvider interface {
+	// ModelName returns the name of the model behind the provider.
+	ModelName() string
+	// PromptVersion identifies the prompt the provider uses for an operation in a locale.
+	PromptVersion(op Operation, locale string) string
+
 	ParseResume(ctx context.Context, file model.File) (model.CandidateResume, error)
 	ParseJobAd(ctx context.Context, file model.File) (model.JobAd, error)
 
-	AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.CandidateAdaptedResume, error)
+	AdaptResume(ctx context.Context, jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error)
 }
 
+type LLMProviderName string
+
 type LLMProviderFactory interface {
-	GetProvider(providerName string) (LLMProvider, error)
+	GetProvider(providerName LLMProviderName) (LLMProvider, error)
+}
+
+// providerCatalog is implemented by factories whose GetProvider refuses providers that are down.
+// DescribeProvider returns them anyway, to compute cache keys without calling them.
+type providerCatalog interface {
+	DescribeProvider(providerName LLMProviderName) (LLMProvider, error)
+}
+
+// describeProvider returns a provider to read its model and prompt versions, even when it is down.
+func (s *SynthesizerService) describeProvider(name LLMProviderName) (LLMProvider, error) {
+	if catalog, ok := s.llmFactory.(providerCatalog); ok {
+		return catalog.DescribeProvider(name)
+	}
+	return s.llmFactory.GetProvider(name)
+}
+
+// promptVersion returns the version of the prompt the provider uses for op in the caller's locale.
+func (s *SynthesizerService) promptVersion(ctx context.Context, op Operation, name LLMProviderName) string {
+	provider, err := s.describeProvider(name)
+	if err != nil {
+		return ""
+	}
+	return provider.PromptVersion(op, LocaleFromContext(ctx))
 }
 
 type SynthesizerService struct {
 	llmFactory LLMProviderFactory
 	repository ResumeRepository
+	routing    Routing
+	logger     zerolog.Logger
+
+	usageRepository UsageRepository
+	prices          PriceTable
+	budgets         budgetStore
+	cache           ExtractionCache
+	cacheTTL        time.Duration
+	extractor       TextExtractor
+
+	chunkMaxChars    int
+	chunkConcurrency int
+	grounding        GroundingMode
+	pseudonymized    map[LLMProviderName]bool
+	taxonomy         *taxonomy.Taxonomy
+	currencies       CurrencyTable
+
+	// candidatesMu serializes the changes to candidates, which read and update several of them.
+	candidatesMu sync.Mutex
 }
 
-func NewSynthesizerService(factory LLMProviderFactory, repo ResumeRepository) *SynthesizerService {
-	return &SynthesizerService{
+// Option configures optional features of the SynthesizerService.
+type Option func(*SynthesizerService)
+
+// WithLogger sets the logger used for failures that do not abort an operation.
+func WithLogger(logger zerolog.Logger) Option {
+	return func(s *SynthesizerService) {
+		s.logger = logger
+	}
+}
+
+func NewSynthesizerService(factory LLMProviderFactory, repo ResumeRepository, routing Routing, opts ...Option) *SynthesizerService {
+	s := &SynthesizerService{
 		llmFactory: factory,
 		repository: repo,
+		routing:    routing,
+		logger:     zerolog.Nop(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-func (s *SynthesizerService) ParseResume(ctx context.Context, file model.File, providerName string) (model.CandidateResume, error) {
-	provider, err := s.llmFactory.GetProvider(providerName)
+// ParseResume parses a resume file with the provider given in opts, or with the configured
+// provider chain for parse_resume when none is given.
+func (s *SynthesizerService) ParseResume(ctx context.Context, file model.File, opts ParseOptions) (model.CandidateResume, error) {
+	resume, usedProvider, cached := lookupExtraction[model.CandidateResume](ctx, s, OperationParseResume, opts.Provider, file.Content, opts)
+	if !cached {
+		if err := s.checkActorBudgets(ctx); err != nil {
+			return model.CandidateResume{}, err
+		}
+	}
+	file, source, err := s.extractText(ctx, file)
+	if err != nil {
+		return model.CandidateResume{}, fmt.Errorf("could not read resume: %w", err)
+	}
+	var usage []model.UsageRecord
+	if !cached {
+		resume, usedProvider, usage, err = callWithFallback(ctx, s, OperationParseResume, opts.Provider,
+			func(ctx context.Context, name LLMProviderName, provider LLMProvider) (model.CandidateResume, error) {
+				return cachedExtraction(ctx, s, OperationParseResume, name, provider, file.Content,
+					func(ctx context.Context) (model.CandidateResume, error) {
+						return pseudonymizedDocument(s, name, file, func(file model.File) (model.CandidateResume, error) {
+							return extractChunked(ctx, s, name, file, provider.ParseResume, mergeResumes, model.CheckResume)
+						})
+					})
+			})
+		if err != nil {
+			s.recordUsage(ctx, usage, "", 0)
+			return model.CandidateResume{}, fmt.Errorf("could not parse resume: %w", err)
+		}
+	}
+	resume.PromptVersion = s.promptVersion(ctx, OperationParseResume, usedProvider)
+	resume.Provider = string(usedProvider)
+	renamed := s.normalizeResumeTerms(&resume, true)
+	s.normalizeResumeRate(&resume)
+	enrichResume(&resume, time.Now())
+	if source != nil {
+		resume.Source = source
+		resume.Provenance = locateFields(resumeFields(resume), renamed, source)
+		resume.Warnings = append(resume.Warnings, source.Warnings...)
+		resume.Suspicious = inspectSource(source)
+		resume.NeedsReview = len(resume.Suspicious) > 0
+	}
+	saved, err := s.repository.SaveResume(ctx, resume)
 	if err != nil {
-		return model.CandidateResume{}, fmt.Errorf("could not get llm provider %s: %w", providerName, err)
+		s.recordUsage(ctx, usage, "", 0)
+		return model.CandidateResume{}, err
 	}
-	resume, err := provider.ParseResume(ctx, file)
+	s.recordUsage(ctx, usage, entityResume, saved.ID)
+	attached, err := s.attachCandidate(ctx, saved)
 	if err != nil {
-		return model.CandidateResume{}, fmt.Errorf("could not parse resume with llm provider %s: %w", providerName, err)
+		return saved, fmt.Errorf("could not attach resume %d to a candidate: %w", saved.ID, err)
 	}
-	return s.repository.SaveResume(ctx, resume)
+	return attached, nil
 }
 
 func (s *SynthesizerService) GetResume(ctx context.Context, resumeID int) (model.CandidateResume, error) {
-	return s.repository.GetResume(ctx, resumeID)
+	resume, err := s.repository.GetResume(ctx, resumeID)
+	if err != nil {
+		return model.CandidateResume{}, err
+	}
+	return refreshResume(resume, time.Now()), nil
 }
 
 func (s *SynthesizerService) UpdateResume(ctx context.Context, resume model.CandidateResume) (model.CandidateResume, error) {
+	existing, err := s.repository.GetResume(ctx, resume.ID)
+	if err != nil {
+		return model.CandidateResume{}, err
+	}
+	resume.CandidateID = existing.CandidateID
+	s.normalizeResumeTerms(&resume, false)
+	s.normalizeResumeRate(&resume)
+	enrichResume(&resume, time.Now())
 	return s.repository.UpdateResume(ctx, resume)
 }
 
 func (s *SynthesizerService) DeleteResume(ctx context.Context, resumeID int) error {
-	return s.repository.DeleteResume(ctx, resumeID)
+	resume, err := s.repository.GetResume(ctx, resumeID)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.DeleteResume(ctx, resumeID); err != nil {
+		return err
+	}
+	return s.detachResume(ctx, resume)
 }
 
 func (s *SynthesizerService) ListResumes(ctx context.Context) ([]model.CandidateResume, error) {
-	return s.repository.ListResumes(ctx)
+	resumes, err := s.repository.ListResumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range resumes {
+		resumes[i] = refreshResume(resumes[i], now)
+	}
+	return resumes, nil
 }
 
 // --- CRUD Operations for JobAds ---
 
-func (s *SynthesizerService) ParseJobAd(ctx context.Context, file model.File, providerName string) (model.JobAd, error) {
-	provider, err := s.llmFactory.GetProvider(providerName)
+func (s *SynthesizerService) ParseJobAd(ctx context.Context, file model.File, opts ParseOptions) (model.JobAd, error) {
+	jobAd, usedProvider, cached := lookupExtraction[model.JobAd](ctx, s, OperationParseJobAd, opts.Provider, file.Content, opts)
+	if !cached {
+		if err := s.checkActorBudgets(ctx); err != nil {
+			return model.JobAd{}, err
+		}
+	}
+	file, source, err := s.extractText(ctx, file)
 	if err != nil {
-		return model.JobAd{}, fmt.Errorf("could not get llm provider %s: %w", providerName, err)
+		return model.JobAd{}, fmt.Errorf("could not read job ad: %w", err)
 	}
-	jobAd, err := provider.ParseJobAd(ctx, file)
+	var usage []model.UsageRecord
+	if !cached {
+		jobAd, usedProvider, usage, err = callWithFallback(ctx, s, OperationParseJobAd, opts.Provider,
+			func(ctx context.Context, name LLMProviderName, provider LLMProvider) (model.JobAd, error) {
+				return cachedExtraction(ctx, s, OperationParseJobAd, name, provider, file.Content,
+					func(ctx context.Context) (model.JobAd, error) {
+						return extractChunked(ctx, s, name, file, provider.ParseJobAd, mergeJobAds, model.CheckJobAd)
+					})
+			})
+		if err != nil {
+			s.recordUsage(ctx, usage, "", 0)
+			return model.JobAd{}, fmt.Errorf("could not parse job ad: %w", err)
+		}
+	}
+	jobAd.PromptVersion = s.promptVersion(ctx, OperationParseJobAd, usedProvider)
+	jobAd.Provider = string(usedProvider)
+	renamed := s.normalizeJobAdTerms(&jobAd, true)
+	s.normalizeJobAd(&jobAd, time.Now())
+	if source != nil {
+		jobAd.Source = source
+		jobAd.Provenance = locateFields(jobAdFields(jobAd), renamed, source)
+		jobAd.Warnings = append(jobAd.Warnings, source.Warnings...)
+		jobAd.Suspicious = inspectSource(source)
+		jobAd.NeedsReview = len(jobAd.Suspicious) > 0
+	}
+	saved, err := s.repository.SaveJobAd(ctx, jobAd)
 	if err != nil {
-		return model.JobAd{}, fmt.Errorf("could not parse job ad with llm provider %s: %w", providerName, err)
+		s.recordUsage(ctx, usage, "", 0)
+		return model.JobAd{}, err
 	}
-	return s.repository.SaveJobAd(ctx, jobAd)
+	s.recordUsage(ctx, usage, entityJobAd, saved.ID)
+	return saved, nil
 }
 
 func (s *SynthesizerService) GetJobAd(ctx context.Context, jobID int) (model.JobAd, error) {
@@ -97,6 +274,8 @@ func (s *SynthesizerService) GetJobAd(ctx context.Context, jobID int) (model.Job
 }
 
 func (s *SynthesizerService) UpdateJobAd(ctx context.Context, jobAd model.JobAd) (model.JobAd, error) {
+	s.normalizeJobAdTerms(&jobAd, false)
+	s.normalizeJobAd(&jobAd, time.Now())
 	return s.repository.UpdateJobAd(ctx, jobAd)
 }
 
@@ -110,7 +289,11 @@ func (s *SynthesizerService) ListJobAds(ctx context.Context) ([]model.JobAd, err
 
 // --- CRUD Operations for CandidateAdaptedResumes ---
 
-func (s *SynthesizerService) AdaptResume(ctx context.Context, jobAdID int, resumeIDs []int, providerName string) (model.CandidateAdaptedResume, error) {
+func (s *SynthesizerService) AdaptResume(ctx context.Context, jobAdID int, resumeIDs []int, providerName LLMProviderName) (model.CandidateAdaptedResume, error) {
+	if err := s.checkActorBudgets(ctx); err != nil {
+		return model.CandidateAdaptedResume{}, err
+	}
+
 	jobAd, err := s.repository.GetJobAd(ctx, jobAdID)
 	if err != nil {
 		return model.CandidateAdaptedResume{}, fmt.Errorf("failed to retrieve job ad with ID %d: %w", jobAdID, err)
@@ -122,31 +305,80 @@ func (s *SynthesizerService) AdaptResume(ctx context.Context, jobAdID int, resum
 		if err != nil {
 			return model.CandidateAdaptedResume{}, fmt.Errorf("failed to retrieve resume with ID %d: %w", resumeID, err)
 		}
-		resumes[i] = resume
+		resumes[i] = refreshResume(resume, time.Now())
 	}
 
 	if len(resumes) == 0 {
 		return model.CandidateAdaptedResume{}, fmt.Errorf("at least one resume must be provided for adaptation")
 	}
 
-	provider, err := s.llmFactory.GetProvider(providerName)
+	var promptVersion string
+	adaptation, usedProvider, usage, err := callWithFallback(ctx, s, OperationAdaptResume, providerName,
+		func(ctx context.Context, name LLMProviderName, provider LLMProvider) (model.ResumeAdaptation, error) {
+			promptVersion = provider.PromptVersion(OperationAdaptResume, LocaleFromContext(ctx))
+			return pseudonymizedAdaptation(s, name, jobAd, resumes,
+				func(jobAd model.JobAd, resumes []model.CandidateResume) (model.ResumeAdaptation, error) {
+					return provider.AdaptResume(ctx, jobAd, resumes)
+				})
+		})
 	if err != nil {
-		return model.CandidateAdaptedResume{}, fmt.Errorf("could not get LLM provider '%s': %w", providerName, err)
+		s.recordUsage(ctx, usage, "", 0)
+		return model.CandidateAdaptedResume{}, fmt.Errorf("LLM failed to adapt resume: %w", err)
 	}
 
-	adapted, err := provider.AdaptResume(ctx, jobAd, resumes)
-	if err != nil {
-		return model.CandidateAdaptedResume{}, fmt.Errorf("LLM failed to adapt resume: %w", err)
+	adapted := model.CandidateAdaptedResume{
+		JobAdID:         jobAd.ID,
+		SourceResumeIDs: resumeIDs,
+		JobAd:           jobAd,
+		Resume:          adaptation.Resume,
+		Rationales:      adaptation.Rationales,
+		Provider:        string(usedProvider),
+		PromptVersion:   promptVersion,
+		CandidateID:     resumes[0].CandidateID,
+	}
+	for _, resume := range resumes {
+		if resume.CandidateID != adapted.CandidateID {
+			adapted.CandidateID = 0
+		}
+	}
+	s.checkGrounding(&adapted, resumes)
+	s.normalizeResumeTerms(&adapted.Resume, false)
+	s.normalizeResumeRate(&adapted.Resume)
+	enrichResume(&adapted.Resume, time.Now())
+	emphasizeSkills(&adapted.Resume, jobAd)
+	if jobAd.NeedsReview {
+		adapted.NeedsReview = true
+		adapted.ReviewReasons = append(adapted.ReviewReasons, reviewReason("job ad", jobAd.ID, jobAd.Suspicious))
+	}
+	for _, resume := range resumes {
+		if resume.NeedsReview {
+			adapted.NeedsReview = true
+			adapted.ReviewReasons = append(adapted.ReviewReasons, reviewReason("resume", resume.ID, resume.Suspicious))
+		}
 	}
 
-	return s.repository.SaveAdaptedResume(ctx, adapted)
+	saved, err := s.repository.SaveAdaptedResume(ctx, adapted)
+	if err != nil {
+		s.recordUsage(ctx, usage, "", 0)
+		return model.CandidateAdaptedResume{}, err
+	}
+	s.recordUsage(ctx, usage, entityAdaptedResume, saved.ID)
+	return saved, nil
 }
 
 func (s *SynthesizerService) GetAdaptedResume(ctx context.Context, adaptedResumeID int) (model.CandidateAdaptedResume, error) {
-	return s.repository.GetAdaptedResume(ctx, adaptedResumeID)
+	adaptedResume, err := s.repository.GetAdaptedResume(ctx, adaptedResumeID)
+	if err != nil {
+		return model.CandidateAdaptedResume{}, err
+	}
+	adaptedResume.Resume = refreshResume(adaptedResume.Resume, time.Now())
+	return adaptedResume, nil
 }
 
 func (s *SynthesizerService) UpdateAdaptedResume(ctx context.Context, adaptedResume model.CandidateAdaptedResume) (model.CandidateAdaptedResume, error) {
+	s.normalizeResumeTerms(&adaptedResume.Resume, false)
+	s.normalizeResumeRate(&adaptedResume.Resume)
+	enrichResume(&adaptedResume.Resume, time.Now())
 	return s.repository.UpdateAdaptedResume(ctx, adaptedResume)
 }
 
@@ -155,5 +387,13 @@ func (s *SynthesizerService) DeleteAdaptedResume(ctx context.Context, adaptedRes
 }
 
 func (s *SynthesizerService) ListAdaptedResumes(ctx context.Context) ([]model.CandidateAdaptedResume, error) {
-	return s.repository.ListAdaptedResumes(ctx)
+	adaptedResumes, err := s.repository.ListAdaptedResumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range adaptedResumes {
+		adaptedResumes[i].Resume = refreshResume(adaptedResumes[i].Resume, now)
+	}
+	return adaptedResumes, nil
 }
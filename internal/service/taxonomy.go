@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mfreyr/deckgen/internal/model"
+	"github.com/mfreyr/deckgen/internal/taxonomy"
+)
+
+// WithTaxonomy replaces the skills and tools of parsed resumes and job ads with their canonical
+// names in t, and completes the category and parents of resume skills.
+func WithTaxonomy(t *taxonomy.Taxonomy) Option {
+	return func(s *SynthesizerService) {
+		s.taxonomy = t
+	}
+}
+
+// renamedTerms maps the canonical names given by the taxonomy to the spellings they replaced.
+type renamedTerms map[string][]string
+
+func (r renamedTerms) add(original, canonical string) {
+	original = strings.TrimSpace(original)
+	if original != canonical && !slices.Contains(r[canonical], original) {
+		r[canonical] = append(r[canonical], original)
+	}
+}
+
+// normalizeResumeTerms maps the skills and tools of resume to the taxonomy, keeping the names it
+// does not know. Those are counted as unmapped only when resume was just parsed, so that editing
+// a resume does not count its terms again. It returns the terms it renamed.
+func (s *SynthesizerService) normalizeResumeTerms(resume *model.CandidateResume, parsed bool) renamedTerms {
+	renamed := make(renamedTerms)
+	if s.taxonomy == nil {
+		return renamed
+	}
+	for i := range resume.Skills {
+		skill := &resume.Skills[i]
+		term, ok := s.lookupTerm(skill.Name, parsed)
+		if !ok {
+			continue
+		}
+		renamed.add(skill.Name, term.Name)
+		skill.Name = term.Name
+		if term.Category != "" {
+			skill.Category = term.Category
+		}
+		skill.Parents = s.taxonomy.Ancestors(term.Name)
+	}
+	for i := range resume.Experiences {
+		experience := &resume.Experiences[i]
+		if strings.TrimSpace(experience.Tools) == "" {
+			continue
+		}
+		tools := s.canonicalTerms(toolSeparators.Split(strings.TrimSpace(experience.Tools), -1), renamed, parsed)
+		experience.Tools = strings.Join(tools, ", ")
+	}
+	return renamed
+}
+
+// normalizeJobAdTerms maps the skills of jobAd to the taxonomy, like normalizeResumeTerms. It
+// returns the terms it renamed.
+func (s *SynthesizerService) normalizeJobAdTerms(jobAd *model.JobAd, parsed bool) renamedTerms {
+	renamed := make(renamedTerms)
+	if s.taxonomy == nil {
+		return renamed
+	}
+	jobAd.Skills = s.canonicalTerms(jobAd.Skills, renamed, parsed)
+	return renamed
+}
+
+// canonicalTerms returns the canonical names of terms, without duplicates, recording those it
+// renames in renamed.
+func (s *SynthesizerService) canonicalTerms(terms []string, renamed renamedTerms, parsed bool) []string {
+	canonical := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if t, ok := s.lookupTerm(term, parsed); ok {
+			renamed.add(term, t.Name)
+			term = t.Name
+		}
+		canonical = appendUnique(canonical, term)
+	}
+	return slices.Clip(canonical)
+}
+
+// lookupTerm finds name in the taxonomy, counting it as unmapped if it is unknown and parsed.
+func (s *SynthesizerService) lookupTerm(name string, parsed bool) (taxonomy.Term, bool) {
+	if parsed {
+		return s.taxonomy.Normalize(name)
+	}
+	return s.taxonomy.Lookup(name)
+}
+
+// CanonicalSkill returns the canonical name of a skill, or name when the taxonomy does not know it.
+func (s *SynthesizerService) CanonicalSkill(name string) string {
+	if s.taxonomy == nil {
+		return name
+	}
+	if term, ok := s.taxonomy.Lookup(name); ok {
+		return term.Name
+	}
+	return name
+}
+
+func (s *SynthesizerService) TaxonomyTerms() []taxonomy.Term {
+	if s.taxonomy == nil {
+		return []taxonomy.Term{}
+	}
+	return s.taxonomy.Terms()
+}
+
+// UnmappedTerms returns the skills and tools met while parsing that the taxonomy does not know.
+func (s *SynthesizerService) UnmappedTerms() []taxonomy.UnmappedTerm {
+	if s.taxonomy == nil {
+		return []taxonomy.UnmappedTerm{}
+	}
+	return s.taxonomy.Unmapped()
+}
+
+// PutTaxonomyTerm adds or replaces a term of the taxonomy. Parsed entities keep their names until
+// they are parsed or updated again.
+func (s *SynthesizerService) PutTaxonomyTerm(term taxonomy.Term) (taxonomy.Term, error) {
+	if s.taxonomy == nil {
+		return taxonomy.Term{}, fmt.Errorf("%w: no taxonomy is configured", ErrInvalidArgument)
+	}
+	if term.Category != "" && !slices.Contains(model.SkillCategories, strings.ToLower(term.Category)) {
+		return taxonomy.Term{}, fmt.Errorf("%w: category must be one of %s",
+			ErrInvalidArgument, strings.Join(model.SkillCategories, ", "))
+	}
+	saved, err := s.taxonomy.Put(term)
+	if errors.Is(err, taxonomy.ErrInvalidTerm) {
+		return taxonomy.Term{}, fmt.Errorf("%w: %w", ErrInvalidArgument, err)
+	}
+	if err != nil {
+		return taxonomy.Term{}, err
+	}
+	s.logger.Info().Str("term", saved.Name).Msg("taxonomy term updated")
+	return saved, nil
+}
+
+func (s *SynthesizerService) DeleteTaxonomyTerm(name string) error {
+	if s.taxonomy == nil {
+		return fmt.Errorf("taxonomy term '%s': %w", name, ErrNotFound)
+	}
+	err := s.taxonomy.Delete(name)
+	if errors.Is(err, taxonomy.ErrUnknownTerm) {
+		return fmt.Errorf("taxonomy term '%s': %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	s.logger.Info().Str("term", name).Msg("taxonomy term deleted")
+	return nil
+}
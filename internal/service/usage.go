@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mfreyr/deckgen/internal/model"
+)
+
+const (
+	entityResume        = "resume"
+	entityJobAd         = "job_ad"
+	entityAdaptedResume = "adapted_resume"
+)
+
+type UsageRepository interface {
+	SaveUsage(ctx context.Context, record model.UsageRecord) (model.UsageRecord, error)
+	ListUsage(ctx context.Context, filter model.UsageFilter) ([]model.UsageRecord, error)
+}
+
+// ModelPrice is the price in USD per million tokens of a model.
+type ModelPrice struct {
+	InputPerMillion       float64
+	CachedInputPerMillion float64
+	OutputPerMillion      float64
+}
+
+// PriceTable maps model names to their price.
+type PriceTable map[string]ModelPrice
+
+func (pt PriceTable) cost(record model.UsageRecord) float64 {
+	price, ok := pt[record.Model]
+	if !ok {
+		return 0
+	}
+	uncached := record.InputTokens - record.CachedInputTokens
+	return (float64(uncached)*price.InputPerMillion +
+		float64(record.CachedInputTokens)*price.CachedInputPerMillion +
+		float64(record.OutputTokens)*price.OutputPerMillion) / 1_000_000
+}
+
+type usageMeterKey struct{}
+
+// usageMeter collects the usage reported by a provider during a single call.
+type usageMeter struct {
+	mu      sync.Mutex
+	records []model.UsageRecord
+}
+
+func withUsageMeter(ctx context.Context) (context.Context, *usageMeter) {
+	meter := &usageMeter{}
+	return context.WithValue(ctx, usageMeterKey{}, meter), meter
+}
+
+// RecordUsage is called by providers to report the tokens and upload size consumed by a request.
+// Only Model and the counters of the record are expected to be set.
+func RecordUsage(ctx context.Context, record model.UsageRecord) {
+	meter, ok := ctx.Value(usageMeterKey{}).(*usageMeter)
+	if !ok {
+		return
+	}
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+	meter.records = append(meter.records, record)
+}
+
+func (m *usageMeter) collect() []model.UsageRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.records
+}
+
+// WithUsageTracking persists the usage of every provider call and prices it with the given table.
+func WithUsageTracking(repo UsageRepository, prices PriceTable) Option {
+	return func(s *SynthesizerService) {
+		s.usageRepository = repo
+		s.prices = prices
+	}
+}
+
+// recordUsage stamps the collected usage with the caller and the produced entity and saves it.
+// Failing to save usage is logged and does not fail the operation.
+func (s *SynthesizerService) recordUsage(ctx context.Context, records []model.UsageRecord, entityType string, entityID int) {
+	if s.usageRepository == nil {
+		return
+	}
+	actor := ActorFromContext(ctx)
+	for _, record := range records {
+		record.TenantID = actor.TenantID
+		record.UserID = actor.UserID
+		record.EntityType = entityType
+		record.EntityID = entityID
+		record.CostUSD = s.prices.cost(record)
+		if _, err := s.usageRepository.SaveUsage(ctx, record); err != nil {
+			s.logger.Error().Err(err).Str("operation", record.Operation).Msg("failed to save usage record")
+		}
+	}
+}
+
+// UsageReport aggregates usage between from and to (both optional) by day, tenant, user,
+// provider, model or operation.
+func (s *SynthesizerService) UsageReport(ctx context.Context, groupBy string, from, to time.Time) ([]model.UsageReportRow, error) {
+	if s.usageRepository == nil {
+		return nil, fmt.Errorf("usage tracking is not enabled")
+	}
+	keyOf, err := usageGroupKey(groupBy)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.usageRepository.ListUsage(ctx, model.UsageFilter{From: from, To: to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+
+	rows := make(map[string]*model.UsageReportRow)
+	for _, record := range records {
+		key := keyOf(record)
+		row, ok := rows[key]
+		if !ok {
+			row = &model.UsageReportRow{Key: key}
+			rows[key] = row
+		}
+		// Uploads are recorded apart from the completion that uses them, and are not calls.
+		if record.UploadedBytes == 0 || record.InputTokens+record.OutputTokens > 0 {
+			row.Calls++
+		}
+		row.InputTokens += record.InputTokens
+		row.CachedInputTokens += record.CachedInputTokens
+		row.OutputTokens += record.OutputTokens
+		row.UploadedBytes += record.UploadedBytes
+		row.CostUSD += record.CostUSD
+	}
+
+	report := make([]model.UsageReportRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, *row)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Key < report[j].Key
+	})
+	return report, nil
+}
+
+func usageGroupKey(groupBy string) (func(model.UsageRecord) string, error) {
+	switch groupBy {
+	case "day":
+		return func(r model.UsageRecord) string { return r.CreatedAt.UTC().Format(time.DateOnly) }, nil
+	case "tenant":
+		return func(r model.UsageRecord) string { return r.TenantID }, nil
+	case "user":
+		return func(r model.UsageRecord) string { return r.UserID }, nil
+	case "provider":
+		return func(r model.UsageRecord) string { return r.Provider }, nil
+	case "model":
+		return func(r model.UsageRecord) string { return r.Model }, nil
+	case "operation":
+		return func(r model.UsageRecord) string { return r.Operation }, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot group usage by '%s', expected day, tenant, user, provider, model or operation", ErrInvalidArgument, groupBy)
+	}
+}
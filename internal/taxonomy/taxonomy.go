@@ -0,0 +1,341 @@
+// Package taxonomy maps the many spellings of skills and tools, such as "k8s" and "Kubernetes",
+// to canonical names, each with a category and the broader skill it belongs to.
+package taxonomy
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed taxonomy.yaml
+var embedded []byte
+
+var (
+	// ErrInvalidTerm is returned when an edited term is rejected.
+	ErrInvalidTerm = errors.New("invalid term")
+	// ErrUnknownTerm is returned when a term to remove does not exist.
+	ErrUnknownTerm = errors.New("unknown term")
+)
+
+// Term is a canonical skill name. Parent is the broader skill it belongs to, such as Java for
+// Spring Boot.
+type Term struct {
+	Name     string   `json:"name" yaml:"name"`
+	Category string   `json:"category,omitempty" yaml:"category,omitempty"`
+	Parent   string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Synonyms []string `json:"synonyms,omitempty" yaml:"synonyms,omitempty"`
+}
+
+// UnmappedTerm is a skill name seen while normalizing that the taxonomy does not know.
+type UnmappedTerm struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// file is the format of the embedded taxonomy and of the override file. Removed lists the
+// embedded terms the override file removes.
+type file struct {
+	Terms   []Term   `yaml:"terms"`
+	Removed []string `yaml:"removed,omitempty"`
+}
+
+// Taxonomy is safe for concurrent use.
+type Taxonomy struct {
+	mu           sync.RWMutex
+	overridePath string
+	embedded     map[string]Term
+	overrides    map[string]Term
+	removed      map[string]bool
+	// aliases maps the key of every name and synonym to the key of its term.
+	aliases  map[string]string
+	terms    map[string]Term
+	unmapped map[string]*UnmappedTerm
+}
+
+// Load reads the embedded taxonomy, then the override file at overridePath if it is not empty
+// and exists. Terms of the override file replace the embedded terms with the same name, and
+// edits are saved to it.
+func Load(overridePath string) (*Taxonomy, error) {
+	t := &Taxonomy{
+		overridePath: overridePath,
+		overrides:    make(map[string]Term),
+		removed:      make(map[string]bool),
+		unmapped:     make(map[string]*UnmappedTerm),
+	}
+	base, err := parse(embedded, "embedded taxonomy")
+	if err != nil {
+		return nil, err
+	}
+	t.embedded = termsByKey(base.Terms)
+	if overridePath != "" {
+		raw, err := os.ReadFile(overridePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read taxonomy '%s': %w", overridePath, err)
+		default:
+			override, err := parse(raw, overridePath)
+			if err != nil {
+				return nil, err
+			}
+			t.overrides = termsByKey(override.Terms)
+			for _, name := range override.Removed {
+				t.removed[key(name)] = true
+			}
+		}
+	}
+	terms, aliases, err := build(t.embedded, t.overrides, t.removed)
+	if err != nil {
+		return nil, err
+	}
+	t.terms, t.aliases = terms, aliases
+	return t, nil
+}
+
+func parse(raw []byte, source string) (file, error) {
+	var f file
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return f, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	for i, term := range f.Terms {
+		if strings.TrimSpace(term.Name) == "" {
+			return f, fmt.Errorf("%s: term %d has no name", source, i+1)
+		}
+	}
+	return f, nil
+}
+
+func termsByKey(terms []Term) map[string]Term {
+	byKey := make(map[string]Term, len(terms))
+	for _, term := range terms {
+		byKey[key(term.Name)] = clean(term)
+	}
+	return byKey
+}
+
+// build merges the embedded terms with the overrides, and indexes their names and synonyms.
+// It fails when a name or synonym belongs to two terms.
+func build(embedded, overrides map[string]Term, removed map[string]bool) (map[string]Term, map[string]string, error) {
+	terms := make(map[string]Term, len(embedded)+len(overrides))
+	for k, term := range embedded {
+		if !removed[k] {
+			terms[k] = term
+		}
+	}
+	for k, term := range overrides {
+		terms[k] = term
+	}
+	aliases := make(map[string]string, len(terms))
+	for k, term := range terms {
+		for _, alias := range append([]string{term.Name}, term.Synonyms...) {
+			aliasKey := key(alias)
+			if other, ok := aliases[aliasKey]; ok && other != k {
+				return nil, nil, fmt.Errorf("%w: '%s' is a name or synonym of both %s and %s",
+					ErrInvalidTerm, alias, terms[other].Name, term.Name)
+			}
+			aliases[aliasKey] = k
+		}
+	}
+	return terms, aliases, nil
+}
+
+// apply saves the given overrides and removed terms, and only then makes them current, so that
+// the taxonomy in memory never differs from the override file.
+func (t *Taxonomy) apply(overrides map[string]Term, removed map[string]bool) error {
+	terms, aliases, err := build(t.embedded, overrides, removed)
+	if err != nil {
+		return err
+	}
+	if err := t.save(overrides, removed); err != nil {
+		return err
+	}
+	t.overrides, t.removed, t.terms, t.aliases = overrides, removed, terms, aliases
+	return nil
+}
+
+// Lookup returns the term named or known by a synonym as name.
+func (t *Taxonomy) Lookup(name string) (Term, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	termKey, ok := t.aliases[key(name)]
+	return t.terms[termKey], ok
+}
+
+// Normalize is Lookup for the names met in newly parsed documents: those the taxonomy does not
+// know are counted as unmapped.
+func (t *Taxonomy) Normalize(name string) (Term, bool) {
+	if term, ok := t.Lookup(name); ok {
+		return term, true
+	}
+	k := key(name)
+	if k == "" {
+		return Term{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if unmapped, seen := t.unmapped[k]; seen {
+		unmapped.Count++
+	} else {
+		t.unmapped[k] = &UnmappedTerm{Term: strings.TrimSpace(name), Count: 1}
+	}
+	return Term{}, false
+}
+
+// Ancestors returns the parent of the term named name, its parent and so on.
+func (t *Taxonomy) Ancestors(name string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var ancestors []string
+	seen := make(map[string]bool)
+	termKey, ok := t.aliases[key(name)]
+	for ok && !seen[termKey] {
+		seen[termKey] = true
+		parent := t.terms[termKey].Parent
+		if parent == "" {
+			break
+		}
+		ancestors = append(ancestors, parent)
+		termKey, ok = t.aliases[key(parent)]
+	}
+	return ancestors
+}
+
+// Terms returns every term, sorted by name.
+func (t *Taxonomy) Terms() []Term {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	terms := make([]Term, 0, len(t.terms))
+	for _, term := range t.terms {
+		terms = append(terms, term)
+	}
+	slices.SortFunc(terms, func(a, b Term) int { return strings.Compare(key(a.Name), key(b.Name)) })
+	return terms
+}
+
+// Unmapped returns the names the taxonomy did not know, the most frequent first.
+func (t *Taxonomy) Unmapped() []UnmappedTerm {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	unmapped := make([]UnmappedTerm, 0, len(t.unmapped))
+	for _, u := range t.unmapped {
+		unmapped = append(unmapped, *u)
+	}
+	slices.SortFunc(unmapped, func(a, b UnmappedTerm) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(key(a.Term), key(b.Term))
+	})
+	return unmapped
+}
+
+// Put adds term, or replaces the term with the same name, and saves the overrides. The names it
+// covers are no longer reported as unmapped. The taxonomy is left unchanged when saving fails.
+func (t *Taxonomy) Put(term Term) (Term, error) {
+	term = clean(term)
+	if term.Name == "" {
+		return Term{}, fmt.Errorf("%w: the name must not be empty", ErrInvalidTerm)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := key(term.Name)
+	overrides, removed := maps.Clone(t.overrides), maps.Clone(t.removed)
+	overrides[k] = term
+	delete(removed, k)
+	if err := t.apply(overrides, removed); err != nil {
+		return Term{}, err
+	}
+	for _, alias := range append([]string{term.Name}, term.Synonyms...) {
+		delete(t.unmapped, key(alias))
+	}
+	return term, nil
+}
+
+// Delete removes the term named name and saves the overrides. The taxonomy is left unchanged when
+// saving fails.
+func (t *Taxonomy) Delete(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := key(name)
+	if _, ok := t.terms[k]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTerm, name)
+	}
+	overrides, removed := maps.Clone(t.overrides), maps.Clone(t.removed)
+	delete(overrides, k)
+	if _, ok := t.embedded[k]; ok {
+		removed[k] = true
+	}
+	return t.apply(overrides, removed)
+}
+
+// save writes overrides and removed to the override file, when there is one. It writes a temporary
+// file first so that a failed write never leaves a partial taxonomy.
+func (t *Taxonomy) save(overrides map[string]Term, removed map[string]bool) error {
+	if t.overridePath == "" {
+		return nil
+	}
+	var f file
+	for _, term := range overrides {
+		f.Terms = append(f.Terms, term)
+	}
+	slices.SortFunc(f.Terms, func(a, b Term) int { return strings.Compare(key(a.Name), key(b.Name)) })
+	for k := range removed {
+		f.Removed = append(f.Removed, t.embedded[k].Name)
+	}
+	slices.Sort(f.Removed)
+	raw, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode taxonomy: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.overridePath), filepath.Base(t.overridePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save taxonomy '%s': %w", t.overridePath, err)
+	}
+	// Once renamed the temporary file no longer exists and removing it is a no-op.
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save taxonomy '%s': %w", t.overridePath, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save taxonomy '%s': %w", t.overridePath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save taxonomy '%s': %w", t.overridePath, err)
+	}
+	if err := os.Rename(tmp.Name(), t.overridePath); err != nil {
+		return fmt.Errorf("failed to save taxonomy '%s': %w", t.overridePath, err)
+	}
+	return nil
+}
+
+func clean(term Term) Term {
+	term.Name = strings.TrimSpace(term.Name)
+	term.Category = strings.ToLower(strings.TrimSpace(term.Category))
+	term.Parent = strings.TrimSpace(term.Parent)
+	synonyms := make([]string, 0, len(term.Synonyms))
+	for _, synonym := range term.Synonyms {
+		if synonym = strings.TrimSpace(synonym); synonym != "" && key(synonym) != key(term.Name) {
+			synonyms = append(synonyms, synonym)
+		}
+	}
+	term.Synonyms = synonyms
+	return term
+}
+
+// key compares names regardless of case, spacing, hyphens and underscores.
+func key(name string) string {
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(name))
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,175 @@
+package taxonomy
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPut(t *testing.T) {
+	tests := []struct {
+		name    string
+		term    Term
+		wantErr error
+		lookup  string
+		want    Term
+	}{
+		{
+			name:   "new term",
+			term:   Term{Name: " Temporal ", Category: "Framework", Synonyms: []string{"temporal.io", "temporal"}},
+			lookup: "TEMPORAL.IO",
+			want:   Term{Name: "Temporal", Category: "framework", Synonyms: []string{"temporal.io"}},
+		},
+		{
+			name:   "replaced embedded term",
+			term:   Term{Name: "kubernetes", Category: "devops", Synonyms: []string{"k8s"}},
+			lookup: "k8s",
+			want:   Term{Name: "kubernetes", Category: "devops", Synonyms: []string{"k8s"}},
+		},
+		{
+			name:    "empty name",
+			term:    Term{Name: " "},
+			wantErr: ErrInvalidTerm,
+		},
+		{
+			name:    "synonym of another term",
+			term:    Term{Name: "Container Orchestration", Synonyms: []string{"k8s"}},
+			wantErr: ErrInvalidTerm,
+			lookup:  "k8s",
+			want:    Term{Name: "Kubernetes", Category: "devops", Synonyms: []string{"k8s", "kube"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "taxonomy.yaml")
+			taxonomy, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			_, err = taxonomy.Put(tt.term)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Put() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.lookup == "" {
+				return
+			}
+			got, ok := taxonomy.Lookup(tt.lookup)
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup(%q) = %+v, %v, want %+v", tt.lookup, got, ok, tt.want)
+			}
+			reloaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() after Put error = %v", err)
+			}
+			if got, ok := reloaded.Lookup(tt.lookup); !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reloaded Lookup(%q) = %+v, %v, want %+v", tt.lookup, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPutSaveFailure(t *testing.T) {
+	taxonomy, err := Load(filepath.Join(t.TempDir(), "missing", "taxonomy.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	taxonomy.Normalize("Temporal")
+	if _, err := taxonomy.Put(Term{Name: "Temporal"}); err == nil {
+		t.Fatal("Put() error = nil, want a save error")
+	}
+	if _, ok := taxonomy.Lookup("Temporal"); ok {
+		t.Error("Lookup(Temporal) found a term that failed to save")
+	}
+	if got := taxonomy.Unmapped(); len(got) != 1 {
+		t.Errorf("Unmapped() = %v, want Temporal still unmapped", got)
+	}
+	if err := taxonomy.Delete("Kubernetes"); err == nil {
+		t.Fatal("Delete() error = nil, want a save error")
+	}
+	if _, ok := taxonomy.Lookup("k8s"); !ok {
+		t.Error("Lookup(k8s) lost a term whose removal failed to save")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		put     *Term
+		delete  string
+		wantErr error
+		lookup  string
+		wantOK  bool
+	}{
+		{name: "embedded term", delete: "kubernetes", lookup: "k8s", wantOK: false},
+		{name: "added term", put: &Term{Name: "Temporal"}, delete: "Temporal", lookup: "Temporal", wantOK: false},
+		{name: "replaced embedded term", put: &Term{Name: "Kubernetes", Synonyms: []string{"k3s"}}, delete: "Kubernetes", lookup: "k8s", wantOK: false},
+		{name: "unknown term", delete: "Temporal", wantErr: ErrUnknownTerm, lookup: "Java", wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "taxonomy.yaml")
+			taxonomy, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if tt.put != nil {
+				if _, err := taxonomy.Put(*tt.put); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+			if err := taxonomy.Delete(tt.delete); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, ok := taxonomy.Lookup(tt.lookup); ok != tt.wantOK {
+				t.Errorf("Lookup(%q) found = %v, want %v", tt.lookup, ok, tt.wantOK)
+			}
+			reloaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() after Delete error = %v", err)
+			}
+			if _, ok := reloaded.Lookup(tt.lookup); ok != tt.wantOK {
+				t.Errorf("reloaded Lookup(%q) found = %v, want %v", tt.lookup, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	taxonomy, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, name := range []string{"K8S", "spring-boot", "Temporal", " temporal ", "Airflow", ""} {
+		taxonomy.Normalize(name)
+	}
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "k8s", want: "Kubernetes", wantOK: true},
+		{name: "Spring_Boot", want: "Spring Boot", wantOK: true},
+		{name: "Temporal", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := taxonomy.Normalize(tt.name)
+			if ok != tt.wantOK || got.Name != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.name, got.Name, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	want := []UnmappedTerm{{Term: "Temporal", Count: 3}, {Term: "Airflow", Count: 1}}
+	if got := taxonomy.Unmapped(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmapped() = %v, want %v", got, want)
+	}
+	if _, err := taxonomy.Put(Term{Name: "Workflow", Synonyms: []string{"temporal"}}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	want = []UnmappedTerm{{Term: "Airflow", Count: 1}}
+	if got := taxonomy.Unmapped(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmapped() after Put = %v, want %v", got, want)
+	}
+}